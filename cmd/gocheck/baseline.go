@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/baseline"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+)
+
+// entry returns the baseline entry of an error found in a file, given
+// the file's lines keyed by their rows.
+func entry(path string, lines map[int]string, word checker.SpellingError) baseline.Entry {
+	context := ""
+	if line, ok := lines[word.Row]; ok {
+		context = baseline.Context(line, word.Offset, word.Offset+len(word.Word))
+	}
+
+	return baseline.Entry{
+		File:    relative(path),
+		Word:    word.Word,
+		Kind:    word.Kind.String(),
+		Context: context,
+	}
+}
+
+// entries returns baseline entries of errors of a checked file, in
+// order of the errors.
+func entries(r result) ([]baseline.Entry, error) {
+	lines, err := errorLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]baseline.Entry, len(r.errors))
+	for i, e := range r.errors {
+		found[i] = entry(r.path, lines, e)
+	}
+
+	return found, nil
+}
+
+// errorLines returns lines of the checked content of a file that
+// contain errors, keyed by their rows.
+func errorLines(r result) (map[int]string, error) {
+	rows := make(map[int]bool)
+	for _, e := range r.errors {
+		rows[e.Row] = true
+	}
+
+	content, err := r.source()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	lines := make(map[int]string)
+	reader := bufio.NewReader(content)
+	for row := 0; len(lines) < len(rows); row++ {
+		line, err := reader.ReadString('\n')
+		if rows[row] {
+			lines[row] = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+// writeBaseline writes errors found in results to a baseline file at
+// given path. Returns the number of errors written.
+func writeBaseline(path string, results []result) (int, error) {
+	b := baseline.New()
+	for _, r := range results {
+		if len(r.errors) == 0 {
+			continue
+		}
+
+		found, err := entries(r)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, e := range found {
+			b.Add(e)
+		}
+	}
+
+	return b.Len(), b.WriteFile(path)
+}
+
+// applyBaseline removes errors known in the baseline file at given path
+// from results, which should contain all errors found, including ones
+// that aren't reported. Returns suppressed errors of each file, and baseline
+// entries of checked files that weren't found, that is errors that were
+// fixed. Entries of files with skipped lines are never fixed, as they
+// may be on skipped lines.
+func applyBaseline(path string, results []result) ([]result, []baseline.Entry, error) {
+	b, err := baseline.LoadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var suppressed []result
+	for i, r := range results {
+		if len(r.errors) == 0 {
+			continue
+		}
+
+		found, err := entries(r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		known := result{path: r.path, changed: r.changed}
+		remaining := r.errors[:0]
+		for i, e := range r.errors {
+			if b.Match(found[i]) {
+				known.errors = append(known.errors, e)
+			} else {
+				remaining = append(remaining, e)
+			}
+		}
+
+		results[i].errors = remaining
+		suppressed = append(suppressed, known)
+	}
+
+	checked := make(map[string]bool)
+	for _, r := range results {
+		checked[relative(r.path)] = r.long == nil
+	}
+
+	var fixed []baseline.Entry
+	for _, e := range b.Entries() {
+		if checked[e.File] {
+			fixed = append(fixed, e)
+		}
+	}
+
+	return suppressed, fixed, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/config"
+)
+
+// project is the configuration file used, nil if there's none.
+var project *config.Config
+
+// rules maps names of rules that can be enabled in a configuration file
+// to their flags.
+var rules = map[string]*bool{
+	"repeated":       repeated,
+	"capitalization": capitalized,
+	"compound":       compound,
+	"ignore-upper":   upper,
+}
+
+// applyConfig loads the configuration file given using -config, or found
+// in the current directory or its parents, and uses it for options not
+// set using flags. Ignored words and patterns of both are used.
+func applyConfig() error {
+	path := *configPath
+	if path == "" {
+		found, err := config.Find(".")
+		if err != nil || found == "" {
+			return err
+		}
+
+		path = found
+	}
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if !set["dict"] {
+		dictionaryPaths = cfg.Dictionaries
+	}
+
+	if !set["include"] {
+		includeGlobs = cfg.Include
+	}
+
+	if !set["exclude"] {
+		excludeGlobs = cfg.Exclude
+	}
+
+	if !set["format"] && cfg.Format != "" {
+		*format = cfg.Format
+	}
+
+	ignoredWords = append(ignoredWords, cfg.Ignore...)
+	ignoredPatterns = append(ignoredPatterns, cfg.IgnorePatterns...)
+
+	for _, rule := range cfg.Rules {
+		enabled, ok := rules[rule]
+		if !ok {
+			return fmt.Errorf("%s: unknown rule %q", path, rule)
+		}
+
+		if !set[rule] {
+			*enabled = true
+		}
+	}
+
+	project = cfg
+	return nil
+}
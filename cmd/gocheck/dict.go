@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/personal"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// dictCommands maps names of dict subcommands to functions running
+// them with the subcommand's arguments.
+var dictCommands = map[string]func(args []string){
+	"dump":  dictDump,
+	"merge": dictMerge,
+	"diff":  dictDiff,
+	"add":   dictAdd,
+	"lint":  dictLint,
+}
+
+// dict runs a dict subcommand, used to inspect and edit dictionaries.
+func dict(args []string) {
+	if len(args) == 0 || dictCommands[args[0]] == nil {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	dictCommands[args[0]](args[1:])
+}
+
+// dictDump writes a dictionary to standard output as a sorted list of words.
+func dictDump(args []string) {
+	flags, opts := writeFlags("dict dump")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	write(loadDictionary(flags.Arg(0)), *opts)
+}
+
+// dictMerge writes the union of dictionaries to standard output as a sorted
+// list of words.
+func dictMerge(args []string) {
+	flags, opts := writeFlags("dict merge")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	root := new(loader.Node)
+	for _, path := range flags.Args() {
+		root = loader.Merge(root, loadDictionary(path))
+	}
+
+	write(root, *opts)
+}
+
+// dictDiff writes words removed from, and added to a dictionary, prefixed
+// with "-" and "+" respectively. Removed words are written first, each
+// in lexical order.
+func dictDiff(args []string) {
+	flags := flag.NewFlagSet("dict diff", flag.ExitOnError)
+	flags.Usage = dictUsage
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	old, updated := loadDictionary(flags.Arg(0)), loadDictionary(flags.Arg(1))
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	loader.Difference(old, updated).Walk(func(word string, _ *loader.Node) bool {
+		fmt.Fprintf(out, "-%s\n", word)
+		return true
+	})
+
+	loader.Difference(updated, old).Walk(func(word string, _ *loader.Node) bool {
+		fmt.Fprintf(out, "+%s\n", word)
+		return true
+	})
+}
+
+// dictAdd adds words to the personal dictionary.
+func dictAdd(args []string) {
+	flags := flag.NewFlagSet("dict add", flag.ExitOnError)
+	path := flags.String("personal", "", "Path to the personal dictionary.")
+	flags.Usage = dictUsage
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	dictionary, err := personalDictionary(*path)
+	if err != nil {
+		fatal(err)
+	}
+
+	added, err := personal.Add(dictionary, flags.Args())
+	if err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("- Added %d words to %s.\n", added, dictionary)
+}
+
+// dictLint reports problems found in a dictionary, such as blank lines,
+// and duplicates. If -normalize is used, problems are written to
+// standard error, and the normalized dictionary to standard output.
+// Exits with status 1 if problems are found.
+func dictLint(args []string) {
+	flags, opts := writeFlags("dict lint")
+	normalize := flags.Bool("normalize", false, "Write a normalized, deduplicated, sorted list of words.")
+	maxLine := flags.Int("max-line", 0, "Maximum length of a line in bytes.")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		dictUsage()
+		os.Exit(exitUsage)
+	}
+
+	root, err := loader.LoadFileOptions(flags.Arg(0), loader.Options{MaxLineLength: *maxLine, Validate: true})
+	validationErr, ok := err.(*loader.ValidationError)
+	if err != nil && !ok {
+		fatal(err)
+	}
+
+	report := os.Stdout
+	if *normalize {
+		report = os.Stderr
+		write(root, *opts)
+	}
+
+	var problems []loader.Problem
+	if ok {
+		problems = validationErr.Problems
+	}
+
+	for _, problem := range problems {
+		if problem.Kind == loader.LongLine {
+			fmt.Fprintf(report, "At (%d) %s, longer than %d bytes.\n", problem.Row, problem.Kind, *maxLine)
+		} else {
+			fmt.Fprintf(report, "At (%d) %s %q.\n", problem.Row, problem.Kind, problem.Line)
+		}
+	}
+
+	fmt.Fprintf(report, "- Found a total of %d problems.\n", len(problems))
+	if len(problems) != 0 {
+		os.Exit(exitErrors)
+	}
+}
+
+// writeFlags returns a flag set of a subcommand writing a dictionary,
+// and the options set by its flags.
+func writeFlags(name string) (*flag.FlagSet, *loader.WriteOptions) {
+	opts := new(loader.WriteOptions)
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.BoolVar(&opts.Frequencies, "frequencies", false, "Write frequencies of words.")
+	flags.BoolVar(&opts.Flags, "flags", false, "Write flags of words.")
+	flags.Usage = dictUsage
+
+	return flags, opts
+}
+
+// write writes a dictionary to standard output.
+func write(root *loader.Node, opts loader.WriteOptions) {
+	if _, err := root.WriteToOptions(os.Stdout, opts); err != nil {
+		fatal(err)
+	}
+}
+
+// loadDictionary loads a dictionary file at the given path.
+func loadDictionary(path string) *loader.Node {
+	root, err := loader.LoadFileOptions(path, loader.Options{})
+	if err != nil {
+		fatal(err)
+	}
+
+	return root
+}
+
+// personalDictionary returns path if it's given, and otherwise the
+// default path of the personal dictionary.
+func personalDictionary(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	return personal.Path()
+}
+
+// dictUsage displays a short usage message of dict subcommands.
+func dictUsage() {
+	fmt.Printf(
+		"Usage\n" +
+			"\tgocheck dict dump [-frequencies] [-flags] <dictionarypath>\n" +
+			"\tgocheck dict merge [-frequencies] [-flags] <dictionarypath>...\n" +
+			"\tgocheck dict diff <old> <new>\n" +
+			"\tgocheck dict add [-personal <path>] <word>...\n" +
+			"\tgocheck dict lint [-normalize] [-frequencies] [-flags] [-max-line <n>] <dictionarypath>\n" +
+			"Use -help for more details.\n")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/diff"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+)
+
+// changes returns files changed in the diff given using -diff, or
+// -git-diff, with paths relative to the current directory.
+func changes() ([]diff.File, error) {
+	if *gitRev != "" {
+		return gitChanges(*gitRev)
+	}
+
+	var r io.Reader = os.Stdin
+	if *diffPath != "-" {
+		file, err := os.Open(*diffPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	return diff.Parse(r)
+}
+
+// gitChanges runs git to find changes between rev and the working tree.
+func gitChanges(rev string) ([]diff.File, error) {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := git("diff", "--unified=0", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := diff.Parse(strings.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		files[i].Path = filepath.Join(strings.TrimSpace(root), filepath.FromSlash(files[i].Path))
+	}
+
+	return files, nil
+}
+
+// git runs git with given arguments, and returns its output. Errors
+// include git's error output.
+func git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", &gitError{args, msg}
+		}
+
+		return "", err
+	}
+
+	return stdout.String(), nil
+}
+
+// gitError is an error reported by git.
+type gitError struct {
+	args []string
+	msg  string
+}
+
+// Error returns a description of the error.
+func (e *gitError) Error() string {
+	return "git " + strings.Join(e.args, " ") + ": " + e.msg
+}
+
+// changedFiles returns paths of files changed in the diff that are
+// under given paths, and match -include and -exclude globs, and the
+// changed rows of each file keyed by its path. Returns an error if a
+// changed file can't be found, such as when paths in the diff aren't
+// relative to the current directory.
+func changedFiles(paths []string) ([]string, map[string]map[int]bool, error) {
+	changed, err := changes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	rows := make(map[string]map[int]bool)
+	for _, file := range changed {
+		name := relative(file.Path)
+		if !under(file.Path, paths) || matchAny(excludeGlobs, name) ||
+			(len(includeGlobs) != 0 && !matchAny(includeGlobs, name)) {
+			continue
+		}
+
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("changed file: %v", err)
+		}
+
+		if info.IsDir() { // Submodule
+			continue
+		}
+
+		files = append(files, file.Path)
+		rows[file.Path] = file.Added
+	}
+
+	return files, rows, nil
+}
+
+// under returns true if path is one of, or inside one of, the given
+// paths.
+func under(path string, paths []string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	for _, p := range paths {
+		dir, err := filepath.Abs(p)
+		if err == nil && (abs == dir || strings.HasPrefix(abs, dir+string(filepath.Separator))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// onlyChanged returns errors found on changed rows.
+func onlyChanged(errors []checker.SpellingError, rows map[int]bool) []checker.SpellingError {
+	filtered := errors[:0]
+	for _, e := range errors {
+		if rows[e.Row] {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/config"
+	"github.com/sudo-sturbia/gocheck/v3/internal/filter"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// result contains errors found in a file.
+type result struct {
+	path    string
+	errors  []checker.SpellingError
+	long    *loader.LineLengthError // Lines skipped for being too long, nil if none
+	stats   *checker.Stats          // Statistics of the file, nil unless -stats is used
+	changed map[int]bool            // Rows changed in the diff, nil unless -diff or -git-diff is used
+
+	// source opens the checked content of the file again, such as to
+	// find lines of errors.
+	source func() (io.ReadCloser, error)
+}
+
+// collect returns paths of files to check. Files are used as is, and
+// directories are walked for files matching -include globs, skipping
+// hidden files and ones matching -exclude globs.
+func collect(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name := relative(file)
+			if file != path && (strings.HasPrefix(info.Name(), ".") || matchAny(excludeGlobs, name)) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !info.IsDir() && (len(includeGlobs) == 0 || matchAny(includeGlobs, name)) {
+				files = append(files, file)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// relative returns the slash-separated path of a file relative to the
+// configuration file's directory, or the current directory if there's
+// no configuration file. Globs are matched against it.
+func relative(path string) string {
+	base := "."
+	if project != nil {
+		base = project.Dir()
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// matchAny returns true if name matches any of the given globs.
+func matchAny(globs []string, name string) bool {
+	for _, glob := range globs {
+		if config.Match(glob, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modeOf returns the file-type mode of a file, set using -mode, or the
+// configuration file.
+func modeOf(path string) string {
+	if *mode != "" {
+		return *mode
+	}
+
+	if project != nil {
+		if m := project.ModeOf(relative(path)); m != "" {
+			return m
+		}
+	}
+
+	return filter.Text
+}
+
+// checkFile checks a file using its file-type mode. Returns an error if
+// the mode is unknown, or reading fails.
+func checkFile(c *checker.Checker, dictionary *loader.Node, path string) (result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return result{path: path}, err
+	}
+	defer file.Close()
+
+	r, err := checkReader(c, dictionary, path, file)
+	r.source = func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+
+	return r, err
+}
+
+// checkText checks the content of a file at given path using the file's
+// file-type mode. Returns an error if the mode is unknown.
+func checkText(c *checker.Checker, dictionary *loader.Node, path, content string) (result, error) {
+	r, err := checkReader(c, dictionary, path, strings.NewReader(content))
+	r.source = text(content)
+
+	return r, err
+}
+
+// text returns a function opening given text as the content of a file.
+func text(content string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+// checkReader checks the content of a file at given path, read from r,
+// using the file's file-type mode. Lines are filtered as they're read.
+func checkReader(c *checker.Checker, dictionary *loader.Node, path string, r io.Reader) (result, error) {
+	m := modeOf(path)
+	if m == filter.Text {
+		return check(c, dictionary, path, r)
+	}
+
+	f, err := filter.New(m)
+	if err != nil {
+		return result{path: path}, err
+	}
+
+	return check(c, dictionary, path, filter.NewReader(f, r))
+}
+
+// check checks text of a file at given path read from r, and computes
+// the file's statistics if -stats is used.
+func check(c *checker.Checker, dictionary *loader.Node, path string, r io.Reader) (result, error) {
+	if !*showStats {
+		errors, err := c.CheckReader(context.Background(), dictionary, r)
+		return result{path: path}.set(errors, err)
+	}
+
+	errors, stats, err := c.CheckReaderStats(context.Background(), dictionary, r)
+	return result{path: path, stats: stats}.set(errors, err)
+}
+
+// set sets errors found in a file, and lines skipped for being too
+// long. Returns other errors.
+func (r result) set(errors []checker.SpellingError, err error) (result, error) {
+	r.errors = errors
+	if long, ok := err.(*loader.LineLengthError); ok {
+		r.long = long
+		err = nil
+	}
+
+	return r, err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// group is a distinct error, a word and a kind of error, and its
+// occurrences.
+type group struct {
+	first     checker.SpellingError // First occurrence, used for suggestions
+	locations []location
+}
+
+// location is an occurrence of an error in a file.
+type location struct {
+	path string
+	err  checker.SpellingError
+}
+
+// groupErrors groups errors found in results by their word and kind.
+// Returns groups sorted by their number of occurrences, most frequent
+// first, and otherwise in order of their first occurrence.
+func groupErrors(results []result) []*group {
+	type key struct {
+		word string
+		kind checker.Kind
+	}
+
+	var groups []*group
+	index := make(map[key]*group)
+	for _, r := range results {
+		for _, e := range r.errors {
+			g := index[key{e.Word, e.Kind}]
+			if g == nil {
+				g = &group{first: e}
+				index[key{e.Word, e.Kind}] = g
+				groups = append(groups, g)
+			}
+
+			g.locations = append(g.locations, location{r.path, e})
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].locations) > len(groups[j].locations)
+	})
+
+	return groups
+}
+
+// writeGroups writes each distinct error once, followed by its
+// locations, one line per file.
+func writeGroups(w io.Writer, c *checker.Checker, dictionary *loader.Node, groups []*group) {
+	for _, g := range groups {
+		fmt.Fprintf(w, "\"%s\"%s (%d)%s\n", g.first.Word, kindText(g.first), len(g.locations),
+			suggestionText(c, dictionary, g.first))
+
+		for i, l := range g.locations {
+			switch {
+			case i == 0:
+				fmt.Fprintf(w, "    %s (%d, %d)", l.path, l.err.Row, l.err.Col)
+			case l.path != g.locations[i-1].path:
+				fmt.Fprintf(w, "\n    %s (%d, %d)", l.path, l.err.Row, l.err.Col)
+			default:
+				fmt.Fprintf(w, ", (%d, %d)", l.err.Row, l.err.Col)
+			}
+		}
+
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hookCommands maps names of hook subcommands to functions running them
+// with the subcommand's arguments.
+var hookCommands = map[string]func(args []string){
+	"install":    hookInstall,
+	"pre-commit": hookPreCommit,
+	"commit-msg": hookCommitMsg,
+}
+
+// hooks are names of git hooks installed by hook install, each running
+// the hook subcommand of the same name.
+var hooks = []string{"pre-commit", "commit-msg"}
+
+// trailer matches the first line of a commit message trailer, such as
+// "Signed-off-by: name".
+var trailer = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*:\s`)
+
+// hook runs a hook subcommand, used to check commits using git hooks.
+func hook(args []string) {
+	if len(args) == 0 || hookCommands[args[0]] == nil {
+		hookUsage()
+		os.Exit(exitUsage)
+	}
+
+	hookCommands[args[0]](args[1:])
+}
+
+// hookInstall installs git hooks running gocheck in the current
+// repository. Arguments following the flags are passed to the hooks.
+func hookInstall(args []string) {
+	flags := flag.NewFlagSet("hook install", flag.ExitOnError)
+	force := flags.Bool("force", false, "Replace existing hooks.")
+	flags.Usage = hookUsage
+	flags.Parse(args)
+
+	out, err := git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		fatal(err)
+	}
+
+	dir := strings.TrimSpace(out)
+	if !*force {
+		for _, name := range hooks {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				fatalf("hook %s exists, use -force to replace it", filepath.Join(dir, name))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fatal(err)
+	}
+
+	options := ""
+	for _, arg := range flags.Args() {
+		options += " " + quote(arg)
+	}
+
+	for _, name := range hooks {
+		path := filepath.Join(dir, name)
+		script := fmt.Sprintf("#!/bin/sh\n# Installed by gocheck hook install.\nexec %s hook %s%s \"$@\"\n",
+			quote(executable()), name, options)
+
+		if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+			fatal(err)
+		}
+
+		if err := os.Chmod(path, 0755); err != nil { // In case the hook existed
+			fatal(err)
+		}
+
+		fmt.Printf("- Installed %s.\n", path)
+	}
+}
+
+// hookPreCommit checks the staged content of files staged for commit.
+func hookPreCommit(args []string) {
+	toplevel()
+	if len(parseFlags(args)) != 0 {
+		hookUsage()
+		os.Exit(exitUsage)
+	}
+
+	dictionary := loadDictionaries(hookDictionaries())
+	c := newChecker(dictionary)
+
+	out, err := git("diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		fatal(err)
+	}
+
+	var results []result
+	for _, file := range strings.Split(out, "\x00") {
+		name := relative(file)
+		if file == "" || matchAny(excludeGlobs, name) ||
+			(len(includeGlobs) != 0 && !matchAny(includeGlobs, name)) {
+			continue
+		}
+
+		content, err := git("cat-file", "blob", ":"+file)
+		if err != nil {
+			fatal(err)
+		}
+
+		if strings.IndexByte(content, 0) >= 0 { // Binary file
+			continue
+		}
+
+		r, err := checkText(c, dictionary, filepath.FromSlash(file), content)
+		if err != nil {
+			fatal(err)
+		}
+
+		results = append(results, r)
+	}
+
+	finish(c, dictionary, results)
+}
+
+// hookCommitMsg checks a commit message file, skipping comment lines and
+// trailers.
+func hookCommitMsg(args []string) {
+	toplevel()
+	args = parseFlags(args)
+	if len(args) != 1 {
+		hookUsage()
+		os.Exit(exitUsage)
+	}
+
+	dictionary := loadDictionaries(hookDictionaries())
+	c := newChecker(dictionary)
+
+	content, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	checked := message(string(content), commentChar())
+	r, err := check(c, dictionary, args[0], strings.NewReader(checked))
+	if err != nil {
+		fatal(err)
+	}
+
+	r.source = text(checked)
+
+	finish(c, dictionary, []result{r})
+}
+
+// message returns the text of a commit message to check, with comment
+// lines, lines below a scissors line, and trailers replaced by empty
+// lines, so that rows of errors are kept.
+func message(text, comment string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == comment+" ------------------------ >8 ------------------------" {
+			for j := i; j < len(lines); j++ {
+				lines[j] = ""
+			}
+
+			break
+		}
+
+		if strings.HasPrefix(line, comment) {
+			lines[i] = ""
+		}
+	}
+
+	// Trailers are the last paragraph, unless it's the only one
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+
+	if strings.TrimSpace(strings.Join(lines[:start], "")) == "" || !trailer.MatchString(lines[start]) {
+		return strings.Join(lines, "\n")
+	}
+
+	for _, line := range lines[start:end] {
+		if !trailer.MatchString(line) && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	for i := start; i < end; i++ {
+		lines[i] = ""
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// commentChar returns the character starting comment lines in commit
+// messages, set using git's core.commentChar.
+func commentChar() string {
+	out, err := git("config", "core.commentChar")
+	if c := strings.TrimSpace(out); err == nil && c != "" && c != "auto" {
+		return c
+	}
+
+	return "#"
+}
+
+// toplevel changes the current directory to the top-level directory of
+// the repository, where git runs hooks and paths of staged files start.
+func toplevel() {
+	out, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		fatal(err)
+	}
+
+	if err := os.Chdir(strings.TrimSpace(out)); err != nil {
+		fatal(err)
+	}
+}
+
+// hookDictionaries returns paths of dictionaries given using -dict, or
+// the configuration file.
+func hookDictionaries() []string {
+	if len(dictionaryPaths) == 0 {
+		fatalf("no dictionaries, use -dict or a configuration file")
+	}
+
+	return dictionaryPaths
+}
+
+// executable returns the command used by hooks to run gocheck, "gocheck"
+// if it's found in PATH, or the path of the running executable.
+func executable() string {
+	if _, err := exec.LookPath("gocheck"); err == nil {
+		return "gocheck"
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return "gocheck"
+	}
+
+	return path
+}
+
+// quote quotes s for use as a word in a shell script.
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// hookUsage displays a short usage message of hook subcommands.
+func hookUsage() {
+	fmt.Printf(
+		"Usage\n" +
+			"\tgocheck hook install [-force] [-- <option>...]\n" +
+			"\tgocheck hook pre-commit [options]\n" +
+			"\tgocheck hook commit-msg [options] <messagepath>\n" +
+			"Use -help for more details.\n")
+}
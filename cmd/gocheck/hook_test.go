@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+)
+
+// Test removal of comments, scissors lines, and trailers from commit
+// messages.
+func TestMessage(t *testing.T) {
+	for _, test := range []struct {
+		text, comment, expected string
+	}{
+		{"Fix typo\n", "#", "Fix typo\n"},
+		{"Fixes: a typo\n", "#", "Fixes: a typo\n"},
+		{
+			"Fix typo\n\nBody text\n\nSigned-off-by: name\nCo-authored-by: other\n  continued\n",
+			"#",
+			"Fix typo\n\nBody text\n\n\n\n\n",
+		},
+		{"Fix typo\n\nSigned-off-by: name\n# comment\n", "#", "Fix typo\n\n\n\n"},
+		{"Fix typo\n; comment\n# heading\n", ";", "Fix typo\n\n# heading\n"},
+		{
+			"Fix typo\n# ------------------------ >8 ------------------------\ndiff text\n",
+			"#",
+			"Fix typo\n\n\n",
+		},
+		{
+			"Fix typo\n; ------------------------ >8 ------------------------\ndiff text\n",
+			";",
+			"Fix typo\n\n\n",
+		},
+		{"Fix typo\n\nNote: a trailer\nand a sentence\n", "#", "Fix typo\n\nNote: a trailer\nand a sentence\n"},
+	} {
+		if text := message(test.text, test.comment); text != test.expected {
+			t.Errorf("Expected %q for %q, found %q.", test.expected, test.text, text)
+		}
+	}
+}
@@ -7,62 +7,439 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/sudo-sturbia/gocheck/v3/internal/personal"
 	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/confusion"
 	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/phonetic"
 )
 
-// ignored is a list of words to ignore while spell-checking. -ignore
-// flag is used to specify words.
-type ignored []string
+// wordList is a list of words given using a flag that can be used
+// multiple times, such as -ignore.
+type wordList []string
+
+// kindSet is a set of kinds of errors given as a comma separated list.
+type kindSet map[checker.Kind]bool
+
+// severities maps kinds of errors to severities given using -severity.
+type severities map[checker.Kind]checker.Severity
 
 // Command line flags
 var (
-	ignoredWords = make(ignored, 16)
-	shortH       = flag.Bool("h", false, "Print a short help message.")
-	detailedH    = flag.Bool("help", false, "Print a detailed help message.")
-	upper        = flag.Bool("ignore-upper", false, "By default a word that contains an uppercase letter any where "+
-		"but the start is considered wrong. When this flag is used, this behaviour is disabled.")
+	ignoredWords    = make(wordList, 16)
+	ignoredPatterns = make(wordList, 0)
+	forbiddenWords  = make(wordList, 0)
+	dictionaryPaths = make(wordList, 0)
+	includeGlobs    = make(wordList, 0)
+	excludeGlobs    = make(wordList, 0)
+	reportedKinds   = make(kindSet)
+	failingKinds    = make(kindSet)
+	kindSeverities  = make(severities)
+	shortH          = flag.Bool("h", false, "Print a short help message.")
+	detailedH       = flag.Bool("help", false, "Print a detailed help message.")
+	upper           = flag.Bool("ignore-upper", false, "Accept words that match a dictionary entry regardless of case. "+
+		"By default \"hello\" accepts \"Hello\" and \"HELLO\", while \"iOS\" must match exactly.")
+	compound     = flag.Bool("compound", false, "Accept words formed by joining dictionary words.")
+	compoundMin  = flag.Int("compound-min", 0, "Minimum length of a part of a compound word in bytes.")
+	compoundLink = flag.String("compound-link", "", "Comma separated list of linking morphemes allowed "+
+		"between parts of a compound word.")
+	compoundFlags = flag.Bool("compound-flags", false, "Only accept parts of compound words flagged as such "+
+		"in the dictionary.")
+	repeated    = flag.Bool("repeated", false, "Report consecutive duplicate words, such as \"the the\".")
+	capitalized = flag.Bool("capitalization", false, "Report sentences starting with a lowercase letter, and "+
+		"proper nouns written in lowercase.")
+	confusables = flag.String("confusables", "", "Path to a file of confusable words, such as \"their\" and "+
+		"\"there\", to check against their context.")
+	minSeverity = flag.String("min-severity", checker.Info.String(), "Report only errors at least as severe as "+
+		"given severity (info, warning, or error.)")
+	suggest = flag.Int("suggest", 0, "Print up to n suggestions for each misspelled word.")
+	sounds  = flag.Bool("phonetic", false, "Include words that sound like a misspelled word in suggestions.")
+	layout  = flag.String("keyboard", "", "Keyboard layout (qwerty, azerty, or dvorak) used to rank likely typos "+
+		"first in suggestions.")
+	maxLine = flag.Int("max-line", 0, "Maximum length of a line in bytes, longer lines are skipped and reported. "+
+		"By default lines can be of any length.")
+	workers    = flag.Int("workers", 0, "Number of lines to check concurrently, defaults to the number of CPUs.")
+	configPath = flag.String("config", "", "Path to a configuration file, by default .gocheck.yaml or "+
+		".gocheck.toml is searched for in the current directory and its parents.")
+	mode      = flag.String("mode", "", "File-type mode used for all files (text, markdown, or code.)")
+	format    = flag.String("format", "text", "Output format (text, or json.)")
+	maxErrors = flag.Int("max-errors", 0, "Number of errors tolerated before exiting with status 1.")
+	warnOnly  = flag.Bool("warn-only", false, "Report errors, but always exit with status 0.")
+
+	baselinePath = flag.String("baseline", "", "Path to a baseline file, errors recorded in it aren't reported.")
+	newBaseline  = flag.String("write-baseline", "", "Write found errors to a baseline file at given path.")
+	diffPath     = flag.String("diff", "", "Path to a unified diff, or - for standard input. Only errors on "+
+		"added and modified lines are reported.")
+	gitRev = flag.String("git-diff", "", "Only report errors on lines changed since given git revision.")
+
+	personalPath = flag.String("personal", "", "Path to the personal dictionary, by default gocheck/words.txt "+
+		"in the user's configuration directory.")
+	noPersonal = flag.Bool("no-personal", false, "Don't load the personal dictionary.")
+
+	showStats = flag.Bool("stats", false, "Report statistics of checked files.")
+	grouped   = flag.Bool("group", false, "Report each distinct error once with all of its locations, "+
+		"most frequent first.")
+
+	watching = flag.Bool("watch", false, "Keep checking files as they change, and report new and fixed errors.")
+	interval = flag.Duration("interval", time.Second, "Interval between checks for changed files using -watch.")
 )
 
 func main() {
-	filePath, dictionaryPath := parse()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "dict":
+			dict(os.Args[2:])
+			return
+		case "hook":
+			hook(os.Args[2:])
+			return
+		}
+	}
+
+	paths, dictionaries := parse()
+
+	dictionary := loadDictionaries(dictionaries)
+	c := newChecker(dictionary)
+
+	var (
+		files   []string
+		changed map[string]map[int]bool // Changed rows of files, nil if all rows are checked
+		err     error
+	)
+	if *diffPath != "" || *gitRev != "" {
+		files, changed, err = changedFiles(paths)
+	} else {
+		files, err = collect(paths)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+
+	results := make([]result, 0, len(files))
+	for _, file := range files {
+		r, err := checkFile(c, dictionary, file)
+		if err != nil {
+			fatal(err)
+		}
+
+		if changed != nil {
+			r.changed = changed[file]
+		}
+
+		results = append(results, r)
+	}
+
+	if *watching {
+		output(c, dictionary, results)
+		watch(c, dictionary, paths, results)
+	}
+
+	finish(c, dictionary, results)
+}
+
+// loadDictionaries loads dictionary files, and the personal dictionary
+// unless -no-personal is used, and merges them into one.
+func loadDictionaries(paths []string) *loader.Node {
+	dictionary := loadDictionary(paths[0])
+	for _, path := range paths[1:] {
+		dictionary = loader.Merge(dictionary, loadDictionary(path))
+	}
+
+	if *noPersonal {
+		return dictionary
+	}
+
+	path, err := personalDictionary(*personalPath)
+	if err != nil { // No configuration directory, nothing to load
+		return dictionary
+	}
+
+	words, err := personal.Load(path)
+	if err != nil {
+		fatal(err)
+	}
+
+	return loader.Merge(dictionary, words)
+}
+
+// finish reports errors found in results, and exits with a status of
+// exitErrors if errors that aren't tolerated are found.
+func finish(c *checker.Checker, dictionary *loader.Node, results []result) {
+	if status := exitStatus(output(c, dictionary, results), *maxErrors, *warnOnly); status != exitClean {
+		os.Exit(status)
+	}
+}
 
-	dictionary := loader.LoadFile(dictionaryPath)
+// exitStatus returns the exit status of a check given the number of
+// failing errors, the number of tolerated errors, and whether errors
+// are only warnings.
+func exitStatus(failing, tolerated int, warnOnly bool) int {
+	if failing > tolerated && !warnOnly {
+		return exitErrors
+	}
+
+	return exitClean
+}
+
+// output reports errors found in results, removing errors that aren't
+// reported from them, or writes them to a baseline file if -write-baseline
+// is used. Returns the number of reported errors that cause failure.
+func output(c *checker.Checker, dictionary *loader.Node, results []result) int {
+	least := leastSeverity()
+	if *newBaseline != "" {
+		filterReported(results, least)
+
+		n, err := writeBaseline(*newBaseline, results)
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("- Wrote %d errors to baseline %s.\n", n, *newBaseline)
+		return 0
+	}
+
+	var err error
+	rep := report{results: results, paths: len(results) > 1, grouped: *grouped}
+	if *baselinePath != "" { // Before filtering, so entries of unreported errors aren't fixed
+		var suppressed []result
+		suppressed, rep.fixed, err = applyBaseline(*baselinePath, results)
+		if err != nil {
+			fatal(err)
+		}
+
+		rep.suppressed = filterReported(suppressed, least)
+	}
+
+	filterReported(results, least)
+
+	if *showStats {
+		rep.stats = checker.NewStats()
+		for _, r := range results {
+			r.stats.SetErrors(r.errors)
+			rep.stats.Add(r.stats)
+		}
+	}
+
+	failing := failingErrors(results, failingKinds)
+	switch *format {
+	case "json":
+		err = writeJSON(os.Stdout, c, dictionary, rep)
+	default:
+		err = writeText(os.Stdout, c, dictionary, rep)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
 
+	return failing
+}
+
+// failingErrors returns the number of errors in results of given kinds,
+// or of all errors if kinds is empty.
+func failingErrors(results []result, kinds kindSet) int {
+	failing := 0
+	for _, r := range results {
+		for _, e := range r.errors {
+			if len(kinds) == 0 || kinds[e.Kind] {
+				failing++
+			}
+		}
+	}
+
+	return failing
+}
+
+// leastSeverity returns the severity given using -min-severity.
+func leastSeverity() checker.Severity {
+	least, err := checker.ParseSeverity(*minSeverity)
+	if err != nil {
+		fatal(err)
+	}
+
+	return least
+}
+
+// Exit statuses.
+const (
+	exitClean  = 0 // No errors found, or errors are tolerated
+	exitErrors = 1 // Errors found
+	exitUsage  = 2 // Invalid usage, or a failure reading files
+)
+
+// fatal prints v, and exits with status exitUsage.
+func fatal(v ...interface{}) {
+	log.Print(v...)
+	os.Exit(exitUsage)
+}
+
+// fatalf prints a formatted message, and exits with status exitUsage.
+func fatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	os.Exit(exitUsage)
+}
+
+// newChecker creates a Checker using command line flags.
+func newChecker(dictionary *loader.Node) *checker.Checker {
 	c := checker.New()
 	c.IgnoreList(ignoredWords)
+	for _, pattern := range ignoredPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fatal(err)
+		}
+
+		c.IgnorePattern(re)
+	}
+
 	c.SetIgnoreUppercase(*upper)
+	c.SetWorkers(*workers)
+	c.SetMaxLineLength(*maxLine)
+	c.SetDetectRepeated(*repeated)
+	c.SetCheckCapitalization(*capitalized)
+	c.SetMaxSuggestions(*suggest)
+	if *compound {
+		opts := &checker.CompoundOptions{MinPartLength: *compoundMin, RequireFlags: *compoundFlags}
+		if *compoundLink != "" {
+			opts.Linking = strings.Split(*compoundLink, ",")
+		}
 
-	errors, err := c.CheckFile(dictionary, filePath)
-	if err != nil {
-		log.Fatal(err)
+		c.SetCompound(opts)
+	}
+
+	if *layout != "" {
+		keyboard, err := checker.ParseLayout(*layout)
+		if err != nil {
+			fatal(err)
+		}
+
+		c.SetKeyboard(keyboard)
+	}
+
+	if *sounds && *suggest > 0 {
+		c.SetPhoneticIndex(phonetic.NewIndex(dictionary))
+	}
+
+	if *confusables != "" {
+		set, err := confusion.LoadFile(*confusables)
+		if err != nil {
+			fatal(err)
+		}
+
+		c.SetConfusables(set)
+	}
+
+	c.ForbidList(forbiddenWords)
+	for kind, severity := range kindSeverities {
+		c.SetSeverity(kind, severity)
+	}
+
+	return c
+}
+
+// reported returns errors that are at least as severe as least, and of
+// kinds set using -kinds.
+func reported(errors []checker.SpellingError, least checker.Severity) []checker.SpellingError {
+	filtered := errors[:0]
+	for _, e := range errors {
+		if e.Severity >= least && (len(reportedKinds) == 0 || reportedKinds[e.Kind]) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
+
+// filterReported removes errors that aren't reported from results, see
+// reported, and errors on unchanged rows of files in diff mode. Returns
+// the number of remaining errors.
+func filterReported(results []result, least checker.Severity) int {
+	n := 0
+	for i, r := range results {
+		errors := reported(r.errors, least)
+		if r.changed != nil {
+			errors = onlyChanged(errors, r.changed)
+		}
+
+		results[i].errors = errors
+		n += len(errors)
+	}
+
+	return n
+}
+
+// parse parses command line arguments and flags, and the configuration
+// file. Returns paths of files and directories to verify, and paths of
+// dictionary files.
+func parse() ([]string, []string) {
+	args := parseFlags(os.Args[1:])
+
+	paths, dictionaries, ok := arguments(args, dictionaryPaths)
+	if !ok { // Print short help message
+		usage()
+		os.Exit(exitUsage)
 	}
 
-	for _, word := range errors {
-		fmt.Printf("At (%d, %d) \"%s\"\n", word.Row, word.Col, word.Word)
+	return paths, dictionaries
+}
+
+// arguments splits command line arguments into paths to check, and
+// paths of dictionaries, given dictionaries from -dict or the
+// configuration file. If there are none, two arguments are a file and a
+// dictionary, and otherwise all arguments are paths. Returns false if
+// arguments are invalid.
+func arguments(args, dictionaries []string) ([]string, []string, bool) {
+	if len(dictionaries) != 0 {
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		return args, dictionaries, true
 	}
 
-	fmt.Printf("- Found a total of %d errors.\n", len(errors))
+	if len(args) != 2 {
+		return nil, nil, false
+	}
+
+	return args[:1], args[1:], true
 }
 
-// parse parses command line arguments and flags. Returns two paths,
-// a file to verify, and a dictionary file.
-func parse() (string, string) {
+// parseFlags parses flags in args, and the configuration file. Returns
+// arguments following the flags.
+func parseFlags(args []string) []string {
 	flag.Var(&ignoredWords, "ignore", "Ignore given word (consider it correct.)")
-	flag.Parse()
+	flag.Var(&ignoredPatterns, "ignore-pattern", "Ignore words matching given regular expression.")
+	flag.Var(&forbiddenWords, "forbid", "Report given word as an error, even if it's in the dictionary.")
+	flag.Var(&dictionaryPaths, "dict", "Path to a dictionary file, can be used multiple times.")
+	flag.Var(&includeGlobs, "include", "Glob of files to check in directories.")
+	flag.Var(&excludeGlobs, "exclude", "Glob of files and directories not to check.")
+	flag.Var(reportedKinds, "kinds", "Comma separated list of kinds of errors to report, by default all are reported.")
+	flag.Var(failingKinds, "fail-on", "Comma separated list of kinds of errors that cause an exit status of 1 "+
+		"when reported, by default all do.")
+	flag.Var(kindSeverities, "severity", "Set severity of a kind of errors, given as <kind>=<severity>.")
+	flag.Usage = usage
+	flag.CommandLine.Parse(args)
 
 	help()
 
-	file := flag.Arg(0)
-	dictionary := flag.Arg(1)
-	if file == "" || dictionary == "" { // Print short help message
-		usage()
-		os.Exit(0)
+	if err := applyConfig(); err != nil {
+		fatal(err)
+	}
+
+	if *format != "text" && *format != "json" {
+		fatalf("unknown output format %q", *format)
 	}
 
-	return file, dictionary
+	if *watching && (*format != "text" || *diffPath != "" || *gitRev != "" || *baselinePath != "" || *newBaseline != "") {
+		fatalf("-watch can't be used with -format json, -diff, -git-diff, -baseline, or -write-baseline")
+	}
+
+	return flag.Args()
 }
 
 // help prints a short or a detailed help message, if -h or -help
@@ -70,12 +447,12 @@ func parse() (string, string) {
 func help() {
 	if *shortH {
 		usage()
-		os.Exit(0)
+		os.Exit(exitClean)
 	}
 
 	if *detailedH {
 		man()
-		os.Exit(0)
+		os.Exit(exitClean)
 	}
 }
 
@@ -84,6 +461,9 @@ func usage() {
 	fmt.Printf(
 		"Usage\n" +
 			"\tgocheck [options] <filepath> <dictionarypath>\n" +
+			"\tgocheck [options] -dict <dictionarypath> [<path>...]\n" +
+			"\tgocheck dict <command> [options] <dictionarypath>\n" +
+			"\tgocheck hook <command> [options]\n" +
 			"Use -help for more details.\n")
 }
 
@@ -97,27 +477,186 @@ func man() {
 			"\n" +
 			"Usage\n" +
 			"\tgocheck [options] <filepath> <dictionarypath>\n" +
+			"\tgocheck [options] -dict <dictionarypath> [<path>...]\n" +
+			"\tgocheck dict <command> [options] <dictionarypath>\n" +
+			"\tgocheck hook <command> [options]\n" +
 			"\n" +
-			"Required Arguments\n" +
+			"Arguments\n" +
 			"\t<filepath>        Path to a text file to spellcheck.\n" +
 			"\t<dictionarypath>  Path to a text file containing a list of words, one word per\n" +
 			"\t                  line, to spellcheck against.\n" +
+			"\t<path>            Path to a file or a directory to spellcheck, defaults to the\n" +
+			"\t                  current directory. Used if dictionaries are given using\n" +
+			"\t                  -dict, or a configuration file, in which case all arguments\n" +
+			"\t                  are paths.\n" +
 			"\n" +
 			"Options\n" +
 			"\t-h              Print a short help message.\n" +
 			"\t-help           Print a detailed help message.\n" +
+			"\t-dict <path>    Path to a dictionary file, can be used multiple times.\n" +
+			"\t-config <path>  Path to a configuration file, see Configuration.\n" +
+			"\t-personal <path>\n" +
+			"\t                Path to the personal dictionary, loaded in addition to other\n" +
+			"\t                dictionaries if it exists. Defaults to gocheck/words.txt in\n" +
+			"\t                the user's configuration directory, see dict add.\n" +
+			"\t-no-personal    Don't load the personal dictionary.\n" +
 			"\t-ignore <word>  Ignore given word (consider it correct.)\n" +
-			"\t-ignore-upper   By default a word that contains an uppercase letter any where\n" +
-			"\t                but the start is considered wrong. When this flag is used, this\n" +
-			"\t                behaviour is disabled.\n" +
+			"\t-ignore-pattern <regexp>\n" +
+			"\t                Ignore words matching given regular expression.\n" +
+			"\t-forbid <word>  Report given word as an error, even if it's in the dictionary.\n" +
+			"\t-ignore-upper   Accept words that match a dictionary entry regardless of case.\n" +
+			"\t                By default a lowercase entry (\"hello\") accepts Title-case\n" +
+			"\t                (\"Hello\") and ALL-CAPS (\"HELLO\") words, a Title-case entry\n" +
+			"\t                (\"Paris\") accepts ALL-CAPS and lowercase words, and any other\n" +
+			"\t                entry (\"iOS\") must be matched exactly.\n" +
+			"\t-compound       Accept compound words, words formed by joining two or more\n" +
+			"\t                dictionary words, such as \"Arbeitszeit\" in German.\n" +
+			"\t-compound-min <n>\n" +
+			"\t                Minimum length of a part of a compound word in bytes,\n" +
+			"\t                defaults to 3.\n" +
+			"\t-compound-link <morphemes>\n" +
+			"\t                Comma separated list of linking morphemes allowed between\n" +
+			"\t                parts of a compound word, such as \"s,en\".\n" +
+			"\t-compound-flags\n" +
+			"\t                Only accept parts of compound words flagged as such in the\n" +
+			"\t                dictionary, given as a tab separated column of letters after\n" +
+			"\t                each word: B (may begin a compound), M (middle), E (end),\n" +
+			"\t                C (anywhere), and N (never, regardless of this option.)\n" +
+			"\t-repeated       Report consecutive duplicate words, such as \"the the\".\n" +
+			"\t-capitalization\n" +
+			"\t                Report sentences starting with a lowercase letter, and proper\n" +
+			"\t                nouns (words that only exist in the dictionary in Title-case)\n" +
+			"\t                written in lowercase.\n" +
+			"\t-confusables <path>\n" +
+			"\t                Path to a file of confusable words, such as \"their\" and\n" +
+			"\t                \"there\", to check against their context. See\n" +
+			"\t                data/confusables.txt for the format.\n" +
+			"\t-kinds <kinds>  Comma separated list of kinds of errors to report, by default\n" +
+			"\t                all are reported.\n" +
+			"\t-min-severity <severity>\n" +
+			"\t                Report only errors at least as severe as given severity.\n" +
+			"\t-severity <kind>=<severity>\n" +
+			"\t                Set severity of a kind of errors, can be used multiple times.\n" +
+			"\t-fail-on <kinds>\n" +
+			"\t                Comma separated list of kinds of errors that cause an exit\n" +
+			"\t                status of 1 when reported, by default all do.\n" +
+			"\t-max-errors <n> Number of reported errors tolerated before exiting with\n" +
+			"\t                status 1, defaults to 0.\n" +
+			"\t-warn-only      Report errors, but exit with status 0 regardless.\n" +
+			"\t-suggest <n>    Print up to n suggestions for each misspelled word. Suggestions\n" +
+			"\t                are ranked using word frequencies if the dictionary contains\n" +
+			"\t                them, given as a tab separated column after each word.\n" +
+			"\t-phonetic       Include words that sound like a misspelled word in suggestions,\n" +
+			"\t                such as \"phonetic\" for \"fonetik\".\n" +
+			"\t-keyboard <layout>\n" +
+			"\t                Keyboard layout (qwerty, azerty, or dvorak) used to rank likely\n" +
+			"\t                typos, such as adjacent keys (\"wprd\") and swapped keys (\"teh\"),\n" +
+			"\t                first in suggestions.\n" +
+			"\t-include <glob>\n" +
+			"\t                Check only files matching given glob in directories, can be\n" +
+			"\t                used multiple times. \"**\" matches any number of directories.\n" +
+			"\t-exclude <glob>\n" +
+			"\t                Skip files and directories matching given glob, can be used\n" +
+			"\t                multiple times. Hidden files are always skipped.\n" +
+			"\t-mode <mode>    File-type mode used for all files: text (default), markdown\n" +
+			"\t                (skips code, links, and HTML tags), or code (checks only\n" +
+			"\t                comments.)\n" +
+			"\t-format <format>\n" +
+			"\t                Output format, text (default) or json.\n" +
+			"\t-group          Report each distinct misspelling, a word and kind of error,\n" +
+			"\t                once with its number of occurrences, suggestions, and all\n" +
+			"\t                of its locations, most frequent first. Errors of files are\n" +
+			"\t                omitted from json output, and listed in groups instead.\n" +
+			"\t-stats          Report statistics of each checked file and their total:\n" +
+			"\t                words checked, unique words, reported errors, reported errors\n" +
+			"\t                per checked word, and time spent, and the most frequent\n" +
+			"\t                misspelled words. Words of all checked lines are counted,\n" +
+			"\t                even if only errors of some are reported, such as using -diff.\n" +
+			"\t-write-baseline <path>\n" +
+			"\t                Record found errors in a baseline file at given path, instead\n" +
+			"\t                of reporting them. Errors are recorded by file, word, and the\n" +
+			"\t                words surrounding them, so they survive lines being moved.\n" +
+			"\t-baseline <path>\n" +
+			"\t                Don't report errors recorded in given baseline file, and\n" +
+			"\t                report recorded errors that were fixed.\n" +
+			"\t-diff <path>    Path to a unified diff, or - for standard input. Only files\n" +
+			"\t                in the diff are checked, and only errors on added and modified\n" +
+			"\t                lines are reported. Paths in the diff are relative to the\n" +
+			"\t                current directory, and changed files must exist.\n" +
+			"\t-git-diff <rev> Like -diff, using changes between given git revision and the\n" +
+			"\t                working tree.\n" +
+			"\t-watch          Keep running, checking files again when they change, and\n" +
+			"\t                report new and fixed errors after each check.\n" +
+			"\t-interval <duration>\n" +
+			"\t                Interval between checks for changed files using -watch,\n" +
+			"\t                such as \"500ms\", defaults to 1s.\n" +
+			"\t-max-line <n>   Maximum length of a line in bytes, longer lines are skipped\n" +
+			"\t                and reported. By default lines can be of any length.\n" +
+			"\t-workers <n>    Number of lines to check concurrently, defaults to the\n" +
+			"\t                number of CPUs.\n" +
+			"\n" +
+			"Configuration\n" +
+			"\tOptions can be set in a .gocheck.yaml or .gocheck.toml file, found in the\n" +
+			"\tcurrent directory or its parents. Flags override the file's options, and\n" +
+			"\tglobs and dictionaries are relative to the file's directory. If the file\n" +
+			"\tsets dictionaries, all arguments are paths to check.\n" +
+			"\n" +
+			"\t    dictionaries: [words.txt]\n" +
+			"\t    ignore: [gocheck]\n" +
+			"\t    ignore-patterns: ['^0x[0-9a-f]+$']\n" +
+			"\t    include: [\"**/*.md\"]\n" +
+			"\t    exclude: [vendor]\n" +
+			"\t    modes:\n" +
+			"\t      \"*.md\": markdown\n" +
+			"\t    rules: [repeated, capitalization, compound, ignore-upper]\n" +
+			"\t    format: text\n" +
+			"\n" +
+			"Dictionary Commands\n" +
+			"\tdump [-frequencies] [-flags] <dictionarypath>\n" +
+			"\t                Write a dictionary to standard output as a sorted list of\n" +
+			"\t                words, optionally with frequencies and flags.\n" +
+			"\tmerge [-frequencies] [-flags] <dictionarypath>...\n" +
+			"\t                Write the union of dictionaries to standard output as a\n" +
+			"\t                sorted list of words. Frequencies of words are added.\n" +
+			"\tdiff <old> <new>\n" +
+			"\t                Write words removed from, and added to a dictionary, prefixed\n" +
+			"\t                with \"-\" and \"+\" respectively.\n" +
+			"\tadd [-personal <path>] <word>...\n" +
+			"\t                Add words to the personal dictionary. Words are appended\n" +
+			"\t                safely while other processes add words.\n" +
+			"\tlint [-normalize] [-frequencies] [-flags] [-max-line <n>] <dictionarypath>\n" +
+			"\t                Report blank lines, surrounding whitespace, duplicates, and\n" +
+			"\t                non-printable bytes in a dictionary, and exit with status 1\n" +
+			"\t                if any are found. With -normalize, write a normalized,\n" +
+			"\t                deduplicated, sorted list of words to standard output.\n" +
+			"\n" +
+			"Git Hooks\n" +
+			"\tinstall [-force] [-- <option>...]\n" +
+			"\t                Install pre-commit and commit-msg hooks in the current\n" +
+			"\t                repository, running gocheck with given options. Existing\n" +
+			"\t                hooks are only replaced using -force.\n" +
+			"\tpre-commit [options]\n" +
+			"\t                Check the staged content of files staged for commit.\n" +
+			"\tcommit-msg [options] <messagepath>\n" +
+			"\t                Check a commit message, skipping comment lines, trailers,\n" +
+			"\t                and lines below a scissors line.\n" +
+			"\tHooks use the configuration file, or -dict, for dictionaries.\n" +
+			"\n" +
+			"Exit Status\n" +
+			"\t0  No errors were found, or found errors are tolerated.\n" +
+			"\t1  Errors were found.\n" +
+			"\t2  Invalid usage, or a file couldn't be read.\n" +
+			"\n" +
+			"Kinds of errors are unknown-word, repeated-word, bad-casing, forbidden-word,\n" +
+			"capitalization, and confused-word, and severities are info, warning, and error.\n" +
 			"\n" +
 			"For the source code see [github.com/sudo-sturbia/gocheck]\n")
 }
 
 // String returns string representation.
-func (i *ignored) String() string {
+func (w *wordList) String() string {
 	builder := new(strings.Builder)
-	for _, s := range *i {
+	for _, s := range *w {
 		builder.WriteString(s)
 		builder.WriteByte(' ')
 	}
@@ -125,10 +664,69 @@ func (i *ignored) String() string {
 	return builder.String()
 }
 
-// Set value adds given string to list of ignored strings.
-func (i *ignored) Set(value string) error {
+// Set value adds given string to the list.
+func (w *wordList) Set(value string) error {
 	if value != "" {
-		ignoredWords = append(ignoredWords, value)
+		*w = append(*w, value)
+	}
+	return nil
+}
+
+// String returns string representation.
+func (k kindSet) String() string {
+	names := make([]string, 0, len(k))
+	for _, kind := range checker.Kinds {
+		if k[kind] {
+			names = append(names, kind.String())
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Set adds kinds in a comma separated list to the set.
+func (k kindSet) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		kind, err := checker.ParseKind(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		k[kind] = true
 	}
+
+	return nil
+}
+
+// String returns string representation.
+func (s severities) String() string {
+	pairs := make([]string, 0, len(s))
+	for _, kind := range checker.Kinds {
+		if severity, ok := s[kind]; ok {
+			pairs = append(pairs, kind.String()+"="+severity.String())
+		}
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// Set parses a <kind>=<severity> pair, and adds it to the map.
+func (s severities) Set(value string) error {
+	pair := strings.SplitN(value, "=", 2)
+	if len(pair) != 2 {
+		return fmt.Errorf("expected <kind>=<severity>, found %q", value)
+	}
+
+	kind, err := checker.ParseKind(pair[0])
+	if err != nil {
+		return err
+	}
+
+	severity, err := checker.ParseSeverity(pair[1])
+	if err != nil {
+		return err
+	}
+
+	s[kind] = severity
 	return nil
 }
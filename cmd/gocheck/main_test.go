@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// Test splitting of command line arguments into paths and dictionaries.
+func TestArguments(t *testing.T) {
+	configured := []string{"words.txt"}
+	for _, test := range []struct {
+		args, dictionaries []string
+		paths, expected    []string
+		ok                 bool
+	}{
+		{[]string{"a.txt", "dict.txt"}, nil, []string{"a.txt"}, []string{"dict.txt"}, true},
+		{[]string{"a.txt", "b.txt"}, configured, []string{"a.txt", "b.txt"}, configured, true},
+		{[]string{"a.txt"}, configured, []string{"a.txt"}, configured, true},
+		{[]string{"a.txt", "b.txt", "c.txt"}, configured, []string{"a.txt", "b.txt", "c.txt"}, configured, true},
+		{nil, configured, []string{"."}, configured, true},
+		{[]string{"a.txt"}, nil, nil, nil, false},
+		{[]string{"a.txt", "b.txt", "c.txt"}, nil, nil, nil, false},
+	} {
+		paths, dictionaries, ok := arguments(test.args, test.dictionaries)
+		if ok != test.ok || !reflect.DeepEqual(paths, test.paths) || !reflect.DeepEqual(dictionaries, test.expected) {
+			t.Errorf("Expected %v, %v, %t for %v, found %v, %v, %t.",
+				test.paths, test.expected, test.ok, test.args, paths, dictionaries, ok)
+		}
+	}
+}
+
+// Test exit statuses given failing kinds, tolerated errors, and -warn-only.
+func TestExitStatus(t *testing.T) {
+	results := []result{
+		{path: "a.txt", errors: []checker.SpellingError{{Word: "teh", Kind: checker.UnknownWord}}},
+		{path: "b.txt", errors: []checker.SpellingError{
+			{Word: "the", Kind: checker.RepeatedWord},
+			{Word: "paris", Kind: checker.Capitalization},
+		}},
+		{path: "c.txt"},
+	}
+
+	for _, test := range []struct {
+		kinds     kindSet
+		tolerated int
+		warnOnly  bool
+		failing   int
+		status    int
+	}{
+		{nil, 0, false, 3, exitErrors},
+		{nil, 2, false, 3, exitErrors},
+		{nil, 3, false, 3, exitClean},
+		{nil, 0, true, 3, exitClean},
+		{kindSet{checker.UnknownWord: true}, 0, false, 1, exitErrors},
+		{kindSet{checker.UnknownWord: true}, 1, false, 1, exitClean},
+		{kindSet{checker.ForbiddenWord: true}, 0, false, 0, exitClean},
+		{kindSet{checker.RepeatedWord: true, checker.Capitalization: true}, 1, false, 2, exitErrors},
+	} {
+		failing := failingErrors(results, test.kinds)
+		if status := exitStatus(failing, test.tolerated, test.warnOnly); failing != test.failing || status != test.status {
+			t.Errorf("Expected %d failing errors and status %d for %v, %d tolerated, warn-only %t, found %d and %d.",
+				test.failing, test.status, test.kinds, test.tolerated, test.warnOnly, failing, status)
+		}
+	}
+}
+
+// Test suppression of errors known in a baseline, and reporting of fixed ones.
+func TestApplyBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gocheck")
+	if err != nil {
+		t.Fatalf("Creating a directory failed: %v.", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "baseline.json")
+	n, err := writeBaseline(path, []result{
+		checked("a.txt", "teh end\nsome wrd\n", unknown("teh", 0, 0, 0), unknown("wrd", 1, 1, 5)),
+		checked("b.txt", "a mispeled word\n", unknown("mispeled", 0, 1, 2)),
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 errors written, found %d (%v).", n, err)
+	}
+
+	long := checked("b.txt", "a word\n")
+	long.long = &loader.LineLengthError{Max: 10, Rows: []int{1}}
+
+	for _, test := range []struct {
+		results                      []result
+		remaining, suppressed, fixed []string
+	}{
+		{
+			[]result{
+				checked("a.txt", "teh end\nsome wrd\n", unknown("teh", 0, 0, 0), unknown("wrd", 1, 1, 5)),
+				checked("b.txt", "a mispeled word\n", unknown("mispeled", 0, 1, 2)),
+			},
+			nil, []string{"teh", "wrd", "mispeled"}, nil,
+		},
+		{
+			[]result{
+				checked("a.txt", "teh end\nsome word\n", unknown("teh", 0, 0, 0)),
+				checked("b.txt", "a mispeled word\nnew eror\n", unknown("mispeled", 0, 1, 2), unknown("eror", 1, 1, 4)),
+			},
+			[]string{"eror"}, []string{"teh", "mispeled"}, []string{"wrd"},
+		},
+		{
+			// Errors are matched by their context, not their rows
+			[]result{checked("a.txt", "intro\nteh end\nsome wrd\n", unknown("teh", 1, 0, 0), unknown("wrd", 2, 1, 5))},
+			nil, []string{"teh", "wrd"}, nil,
+		},
+		{
+			// Entries of unchecked files, or files with skipped lines, aren't fixed
+			[]result{long},
+			nil, nil, nil,
+		},
+	} {
+		suppressed, fixed, err := applyBaseline(path, test.results)
+		if err != nil {
+			t.Fatalf("Applying the baseline failed: %v.", err)
+		}
+
+		var fixedWords []string
+		for _, e := range fixed {
+			fixedWords = append(fixedWords, e.Word)
+		}
+
+		if remaining := words(test.results); !reflect.DeepEqual(remaining, test.remaining) ||
+			!reflect.DeepEqual(words(suppressed), test.suppressed) || !reflect.DeepEqual(fixedWords, test.fixed) {
+			t.Errorf("Expected %v remaining, %v suppressed, and %v fixed, found %v, %v, and %v.",
+				test.remaining, test.suppressed, test.fixed, remaining, words(suppressed), fixedWords)
+		}
+	}
+}
+
+// checked returns the result of checking a file with given content.
+func checked(path, content string, errors ...checker.SpellingError) result {
+	return result{path: path, errors: errors, source: text(content)}
+}
+
+// unknown returns an unknown-word error.
+func unknown(word string, row, col, offset int) checker.SpellingError {
+	return checker.SpellingError{Word: word, Row: row, Col: col, Offset: offset, Kind: checker.UnknownWord, Severity: checker.Error}
+}
+
+// words returns words of errors in results.
+func words(results []result) []string {
+	var found []string
+	for _, r := range results {
+		for _, e := range r.errors {
+			found = append(found, e.Word)
+		}
+	}
+
+	return found
+}
+
+// Test filtering of reported errors by severity, and by changed rows in
+// diff mode.
+func TestFilterReported(t *testing.T) {
+	errors := []checker.SpellingError{
+		unknown("teh", 0, 0, 0),
+		unknown("wrd", 1, 0, 0),
+		{Word: "the", Row: 2, Kind: checker.RepeatedWord, Severity: checker.Warning},
+		unknown("eror", 3, 0, 0),
+	}
+
+	for _, test := range []struct {
+		changed  map[int]bool
+		least    checker.Severity
+		expected []string
+	}{
+		{nil, checker.Info, []string{"teh", "wrd", "the", "eror"}},
+		{nil, checker.Error, []string{"teh", "wrd", "eror"}},
+		{map[int]bool{}, checker.Info, nil},
+		{map[int]bool{1: true, 2: true}, checker.Info, []string{"wrd", "the"}},
+		{map[int]bool{1: true, 2: true}, checker.Error, []string{"wrd"}},
+	} {
+		results := []result{{path: "a.txt", errors: append([]checker.SpellingError(nil), errors...), changed: test.changed}}
+		n := filterReported(results, test.least)
+		if found := words(results); n != len(test.expected) || !reflect.DeepEqual(found, test.expected) {
+			t.Errorf("Expected %v for changed rows %v, found %v (%d).", test.expected, test.changed, found, n)
+		}
+	}
+}
+
+// Test grouping of errors by their word and kind, most frequent first.
+func TestGroupErrors(t *testing.T) {
+	repeated := checker.SpellingError{Word: "teh", Row: 2, Kind: checker.RepeatedWord, Severity: checker.Warning}
+	for _, test := range []struct {
+		results  []result
+		expected []string
+	}{
+		{nil, nil},
+		{
+			[]result{
+				{path: "a.txt", errors: []checker.SpellingError{unknown("wrd", 0, 0, 0), unknown("teh", 1, 0, 0)}},
+				{path: "b.txt", errors: []checker.SpellingError{unknown("teh", 0, 2, 4), unknown("wrd", 3, 0, 0)}},
+				{path: "c.txt", errors: []checker.SpellingError{unknown("teh", 5, 1, 2)}},
+			},
+			[]string{"teh unknown-word a.txt:1 b.txt:0 c.txt:5", "wrd unknown-word a.txt:0 b.txt:3"},
+		},
+		{
+			// Ties keep the order of first occurrences, and kinds are grouped apart
+			[]result{{path: "a.txt", errors: []checker.SpellingError{unknown("wrd", 0, 0, 0), repeated, unknown("teh", 3, 0, 0)}}},
+			[]string{"wrd unknown-word a.txt:0", "teh repeated-word a.txt:2", "teh unknown-word a.txt:3"},
+		},
+	} {
+		var found []string
+		for _, g := range groupErrors(test.results) {
+			text := g.first.Word + " " + g.first.Kind.String()
+			for _, l := range g.locations {
+				text += fmt.Sprintf(" %s:%d", l.path, l.err.Row)
+			}
+
+			found = append(found, text)
+		}
+
+		if !reflect.DeepEqual(found, test.expected) {
+			t.Errorf("Expected groups %q, found %q.", test.expected, found)
+		}
+	}
+}
+
+// Test finding errors introduced and fixed since the last check of a
+// watched file.
+func TestChangesSince(t *testing.T) {
+	old := checked("a.txt", "teh end\nsome wrd\n", unknown("teh", 0, 0, 0), unknown("wrd", 1, 1, 5))
+	for _, test := range []struct {
+		old            *result
+		r              result
+		added, removed []string
+	}{
+		{nil, old, []string{"teh", "wrd"}, nil},
+		{&old, checked("a.txt", "intro\nteh end\nsome wrd\n", unknown("teh", 1, 0, 0), unknown("wrd", 2, 1, 5)), nil, nil},
+		{&old, checked("a.txt", "teh end\nsome word eror\n", unknown("teh", 0, 0, 0), unknown("eror", 1, 2, 10)), []string{"eror"}, []string{"wrd"}},
+		{&old, checked("a.txt", "teh end\nteh end\n", unknown("teh", 0, 0, 0), unknown("teh", 1, 0, 0)), []string{"teh"}, []string{"wrd"}},
+	} {
+		var previous *watched
+		if test.old != nil {
+			found, err := entries(*test.old)
+			if err != nil {
+				t.Fatalf("Reading lines failed: %v.", err)
+			}
+
+			previous = &watched{errors: found}
+		}
+
+		found, err := entries(test.r)
+		if err != nil {
+			t.Fatalf("Reading lines failed: %v.", err)
+		}
+
+		added, removed := changesSince(previous, test.r.errors, &watched{errors: found})
+
+		var removedWords []string
+		for _, e := range removed {
+			removedWords = append(removedWords, e.Word)
+		}
+
+		addedWords := words([]result{{errors: added}})
+		if !reflect.DeepEqual(addedWords, test.added) || !reflect.DeepEqual(removedWords, test.removed) {
+			t.Errorf("Expected %v added and %v removed, found %v and %v.", test.added, test.removed, addedWords, removedWords)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/baseline"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// report is the output of a run.
+type report struct {
+	results    []result
+	paths      bool             // If true, paths of files are written
+	suppressed int              // Number of errors known in the baseline
+	fixed      []baseline.Entry // Baseline entries that weren't found
+	stats      *checker.Stats   // Statistics of all files, nil unless -stats is used
+	grouped    bool             // If true, errors are written grouped, see groupErrors
+}
+
+// topMisspellings is the number of most frequent misspellings reported
+// using -stats.
+const topMisspellings = 10
+
+// writeText writes a report as lines of text. Returns the first error
+// writing to out.
+func writeText(out io.Writer, c *checker.Checker, dictionary *loader.Node, rep report) error {
+	w := &errWriter{w: out}
+	total := 0
+	for _, r := range rep.results {
+		listed := !rep.grouped && len(r.errors) != 0
+		if rep.paths && (listed || r.long != nil) {
+			fmt.Fprintf(w, "%s\n", r.path)
+		}
+
+		if r.long != nil {
+			for _, row := range r.long.Rows {
+				fmt.Fprintf(w, "At (%d) line is longer than %d bytes, skipped.\n", row, r.long.Max)
+			}
+		}
+
+		if listed {
+			for _, word := range r.errors {
+				fmt.Fprintln(w, errorMessage(c, dictionary, word))
+			}
+		}
+
+		total += len(r.errors)
+	}
+
+	var groups []*group
+	if rep.grouped {
+		groups = groupErrors(rep.results)
+		writeGroups(w, c, dictionary, groups)
+	}
+
+	for _, e := range rep.fixed {
+		fmt.Fprintln(w, fixedMessage(e))
+	}
+
+	if rep.grouped {
+		fmt.Fprintf(w, "- Found a total of %d errors, %d distinct.\n", total, len(groups))
+	} else {
+		fmt.Fprintf(w, "- Found a total of %d errors.\n", total)
+	}
+	if rep.suppressed != 0 || len(rep.fixed) != 0 {
+		fmt.Fprintf(w, "- Suppressed %d errors known in the baseline, %d fixed.\n", rep.suppressed, fixedCount(rep.fixed))
+	}
+
+	if rep.stats != nil {
+		writeStats(w, rep)
+	}
+
+	return w.err
+}
+
+// errWriter is a writer that records the first error writing to w, and
+// skips writes following it.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write writes p to w, unless a previous write failed.
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	n, err := e.w.Write(p)
+	e.err = err
+
+	return n, err
+}
+
+// writeStats writes statistics of a report as lines of text.
+func writeStats(w io.Writer, rep report) {
+	if rep.paths {
+		for _, r := range rep.results {
+			fmt.Fprintf(w, "%s: %s.\n", r.path, statsText(r.stats))
+		}
+	}
+
+	fmt.Fprintf(w, "- Checked %s.\n", statsText(rep.stats))
+
+	misspellings := rep.stats.Misspellings(topMisspellings)
+	if len(misspellings) == 0 {
+		return
+	}
+
+	counts := make([]string, len(misspellings))
+	for i, m := range misspellings {
+		counts[i] = fmt.Sprintf("\"%s\" (%d)", m.Word, m.Count)
+	}
+
+	fmt.Fprintf(w, "- Most frequent misspellings: %s.\n", strings.Join(counts, ", "))
+}
+
+// statsText returns a description of statistics. Errors are the ones
+// reported, while words include words of all checked rows, so the rate
+// is relative to all of them.
+func statsText(s *checker.Stats) string {
+	return fmt.Sprintf("%d words (%d unique) in %v, %d reported errors (%.2f%% of words)",
+		s.Words, s.Unique(), s.Elapsed.Round(time.Microsecond), s.Errors, 100*s.Rate())
+}
+
+// errorMessage returns the line describing an error in text output.
+func errorMessage(c *checker.Checker, dictionary *loader.Node, word checker.SpellingError) string {
+	return fmt.Sprintf("At (%d, %d) \"%s\"", word.Row, word.Col, word.Word) +
+		kindText(word) + suggestionText(c, dictionary, word)
+}
+
+// kindText returns the kind and severity of an error in text output,
+// empty for the default, unknown words of severity error.
+func kindText(word checker.SpellingError) string {
+	if word.Kind != checker.UnknownWord || word.Severity != checker.Error {
+		return fmt.Sprintf(" %s (%s)", word.Kind, word.Severity)
+	}
+
+	return ""
+}
+
+// suggestionText returns suggestions for an error in text output, empty
+// if there are none.
+func suggestionText(c *checker.Checker, dictionary *loader.Node, word checker.SpellingError) string {
+	if suggestions := suggestions(c, dictionary, word); len(suggestions) != 0 {
+		return fmt.Sprintf(", did you mean \"%s\"?", strings.Join(suggestions, "\", \""))
+	}
+
+	return ""
+}
+
+// fixedMessage returns the line describing a fixed error in text output.
+func fixedMessage(e baseline.Entry) string {
+	text := fmt.Sprintf("Fixed \"%s\" in %s (%q)", e.Word, e.File, e.Context)
+	if e.Count > 1 {
+		text += fmt.Sprintf(" %d times", e.Count)
+	}
+
+	return text + "."
+}
+
+// fixedCount returns the number of fixed occurrences of baseline entries.
+func fixedCount(fixed []baseline.Entry) int {
+	n := 0
+	for _, e := range fixed {
+		n += e.Count
+	}
+
+	return n
+}
+
+// suggestions returns the correction of an error if it has one, and
+// otherwise suggestions for misspelled words, if -suggest is used.
+func suggestions(c *checker.Checker, dictionary *loader.Node, word checker.SpellingError) []string {
+	if correction, ok := word.Correction(); ok {
+		return []string{correction}
+	}
+
+	if *suggest > 0 && (word.Kind == checker.UnknownWord || word.Kind == checker.BadCasing) {
+		return c.Suggest(dictionary, word.Word)
+	}
+
+	return nil
+}
+
+// jsonOutput is the output written using -format json.
+type jsonOutput struct {
+	Files      []jsonFile       `json:"files"`
+	Total      int              `json:"total"`
+	Suppressed int              `json:"suppressed,omitempty"` // Number of errors known in the baseline
+	Fixed      []baseline.Entry `json:"fixed,omitempty"`      // Baseline entries that weren't found
+	Stats      *jsonStats       `json:"stats,omitempty"`
+	Groups     []jsonGroup      `json:"groups,omitempty"` // Distinct errors, using -group
+}
+
+// jsonFile contains errors found in a file.
+type jsonFile struct {
+	Path    string       `json:"path"`
+	Errors  *[]jsonError `json:"errors,omitempty"`  // Omitted using -group, errors are in groups
+	Skipped []int        `json:"skipped,omitempty"` // Rows of lines skipped for being too long
+	Stats   *jsonStats   `json:"stats,omitempty"`
+}
+
+// jsonGroup is a distinct error, and its occurrences.
+type jsonGroup struct {
+	Word        string         `json:"word"`
+	Kind        string         `json:"kind"`
+	Severity    string         `json:"severity"`
+	Count       int            `json:"count"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+	Locations   []jsonLocation `json:"locations"`
+}
+
+// jsonLocation is an occurrence of an error.
+type jsonLocation struct {
+	Path   string `json:"path"`
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	Offset int    `json:"offset"`
+}
+
+// jsonStats are statistics of checked files.
+type jsonStats struct {
+	Words        int               `json:"words"`
+	Unique       int               `json:"unique"`
+	Errors       int               `json:"errors"`
+	Rate         float64           `json:"rate"`    // Reported errors per checked word, including unreported rows
+	Seconds      float64           `json:"seconds"` // Time spent checking
+	Misspellings []jsonMisspelling `json:"misspellings"`
+}
+
+// jsonMisspelling is a misspelled word, and its number of occurrences.
+type jsonMisspelling struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// jsonError is a spelling error.
+type jsonError struct {
+	Word        string   `json:"word"`
+	Row         int      `json:"row"`
+	Col         int      `json:"col"`
+	Offset      int      `json:"offset"`
+	Kind        string   `json:"kind"`
+	Severity    string   `json:"severity"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// writeJSON writes a report as a JSON object.
+func writeJSON(w io.Writer, c *checker.Checker, dictionary *loader.Node, rep report) error {
+	out := jsonOutput{Files: make([]jsonFile, 0, len(rep.results)), Suppressed: rep.suppressed, Fixed: rep.fixed}
+	for _, r := range rep.results {
+		file := jsonFile{Path: r.path}
+		if r.long != nil {
+			file.Skipped = r.long.Rows
+		}
+
+		if !rep.grouped {
+			errors := make([]jsonError, 0, len(r.errors))
+			for _, e := range r.errors {
+				errors = append(errors, jsonError{
+					Word:        e.Word,
+					Row:         e.Row,
+					Col:         e.Col,
+					Offset:      e.Offset,
+					Kind:        e.Kind.String(),
+					Severity:    e.Severity.String(),
+					Suggestions: suggestions(c, dictionary, e),
+				})
+			}
+
+			file.Errors = &errors
+		}
+
+		if r.stats != nil {
+			file.Stats = newJSONStats(r.stats)
+		}
+
+		out.Files = append(out.Files, file)
+		out.Total += len(r.errors)
+	}
+
+	if rep.stats != nil {
+		out.Stats = newJSONStats(rep.stats)
+	}
+
+	if rep.grouped {
+		for _, g := range groupErrors(rep.results) {
+			group := jsonGroup{
+				Word:        g.first.Word,
+				Kind:        g.first.Kind.String(),
+				Severity:    g.first.Severity.String(),
+				Count:       len(g.locations),
+				Suggestions: suggestions(c, dictionary, g.first),
+				Locations:   make([]jsonLocation, 0, len(g.locations)),
+			}
+
+			for _, l := range g.locations {
+				group.Locations = append(group.Locations, jsonLocation{l.path, l.err.Row, l.err.Col, l.err.Offset})
+			}
+
+			out.Groups = append(out.Groups, group)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(out)
+}
+
+// newJSONStats returns JSON output of statistics.
+func newJSONStats(s *checker.Stats) *jsonStats {
+	stats := &jsonStats{
+		Words:        s.Words,
+		Unique:       s.Unique(),
+		Errors:       s.Errors,
+		Rate:         s.Rate(),
+		Seconds:      s.Elapsed.Seconds(),
+		Misspellings: make([]jsonMisspelling, 0),
+	}
+
+	for _, m := range s.Misspellings(topMisspellings) {
+		stats.Misspellings = append(stats.Misspellings, jsonMisspelling{m.Word, m.Count})
+	}
+
+	return stats
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/baseline"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/checker"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// watched is the state of a watched file at its last check.
+type watched struct {
+	modified time.Time
+	size     int64
+	errors   []baseline.Entry // Errors found in the file, keyed by their context
+}
+
+// watch polls files under paths every -interval, checks files that were
+// added or modified again, and reports errors introduced and fixed since
+// their last check. Errors of removed files are reported as fixed. It
+// never returns.
+func watch(c *checker.Checker, dictionary *loader.Node, paths []string, results []result) {
+	files := make(map[string]*watched)
+	for _, r := range results {
+		info, err := os.Stat(r.path)
+		if err != nil {
+			fatal(err)
+		}
+
+		state, err := snapshot(r, info)
+		if err != nil {
+			fatal(err)
+		}
+
+		files[r.path] = state
+	}
+
+	least := leastSeverity()
+	for {
+		time.Sleep(*interval)
+
+		var existing []string
+		for _, path := range paths {
+			if _, err := os.Stat(path); err == nil {
+				existing = append(existing, path)
+			}
+		}
+
+		current, err := collect(existing)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+
+		checked, introduced, fixed := false, 0, 0
+		seen := make(map[string]bool)
+		for _, path := range current {
+			seen[path] = true
+
+			info, err := os.Stat(path)
+			old := files[path]
+			if err != nil || (old != nil && info.ModTime().Equal(old.modified) && info.Size() == old.size) {
+				continue
+			}
+
+			r, err := checkFile(c, dictionary, path)
+			if err != nil {
+				log.Print(err)
+				continue
+			}
+
+			r.errors = reported(r.errors, least)
+			state, err := snapshot(r, info)
+			if err != nil {
+				log.Print(err)
+				continue
+			}
+
+			added, removed := changesSince(old, r.errors, state)
+			if len(added) != 0 || len(removed) != 0 {
+				fmt.Println(path)
+			}
+
+			for _, e := range added {
+				fmt.Println(errorMessage(c, dictionary, e))
+			}
+
+			for _, e := range removed {
+				fmt.Println(fixedMessage(e))
+			}
+
+			files[path] = state
+			checked, introduced, fixed = true, introduced+len(added), fixed+fixedCount(removed)
+		}
+
+		var removed []string
+		for path := range files {
+			if !seen[path] {
+				removed = append(removed, path)
+			}
+		}
+
+		sort.Strings(removed)
+		for _, path := range removed {
+			if len(files[path].errors) != 0 {
+				fmt.Println(path)
+			}
+
+			for _, e := range files[path].errors {
+				fmt.Println(fixedMessage(e))
+			}
+
+			checked, fixed = true, fixed+len(files[path].errors)
+			delete(files, path)
+		}
+
+		if checked {
+			total := 0
+			for _, state := range files {
+				total += len(state.errors)
+			}
+
+			fmt.Printf("- Found a total of %d errors, %d new, %d fixed.\n", total, introduced, fixed)
+		}
+	}
+}
+
+// changesSince returns errors of a checked file that weren't found in
+// its old state, nil if it wasn't checked before, and entries of old
+// errors that weren't found, given the errors and their new state.
+func changesSince(old *watched, errors []checker.SpellingError, state *watched) ([]checker.SpellingError, []baseline.Entry) {
+	known := baseline.New()
+	if old != nil {
+		for _, e := range old.errors {
+			known.Add(e)
+		}
+	}
+
+	var added []checker.SpellingError
+	for i, e := range errors {
+		if !known.Match(state.errors[i]) {
+			added = append(added, e)
+		}
+	}
+
+	return added, known.Entries()
+}
+
+// snapshot returns the state of a checked file, given the result of its
+// check, and its info before the check.
+func snapshot(r result, info os.FileInfo) (*watched, error) {
+	found, err := entries(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &watched{modified: info.ModTime(), size: info.Size(), errors: found}, nil
+}
@@ -0,0 +1,196 @@
+// Package baseline implements baseline files, records of known spelling
+// errors that are suppressed when checking, so only newly introduced
+// errors are reported.
+//
+// Errors are keyed by their file, word, kind, and context (the words
+// surrounding them) rather than their position, so entries survive
+// lines being added or removed elsewhere in a file.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// version is the version of the baseline file format.
+const version = 1
+
+// Entry is a known spelling error.
+type Entry struct {
+	File    string `json:"file"`    // Slash-separated path of the file
+	Word    string `json:"word"`    // Misspelled word
+	Kind    string `json:"kind"`    // Kind of the error
+	Context string `json:"context"` // Word and the words surrounding it, see Context
+	Count   int    `json:"count"`   // Number of occurrences
+}
+
+// key identifies an entry regardless of its count.
+type key struct {
+	file, word, kind, context string
+}
+
+// Baseline is a set of known spelling errors.
+type Baseline struct {
+	counts map[key]int
+}
+
+// file is the content of a baseline file.
+type file struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// New returns a new, empty Baseline.
+func New() *Baseline {
+	return &Baseline{make(map[key]int)}
+}
+
+// LoadFile loads the baseline file at given path. Returns an error if
+// reading, or parsing fails.
+func LoadFile(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return b, nil
+}
+
+// Read reads a baseline from r.
+func Read(r io.Reader) (*Baseline, error) {
+	var content file
+	if err := json.NewDecoder(r).Decode(&content); err != nil {
+		return nil, err
+	}
+
+	if content.Version != version {
+		return nil, fmt.Errorf("unsupported baseline version %d", content.Version)
+	}
+
+	b := New()
+	for _, e := range content.Entries {
+		b.Add(e)
+	}
+
+	return b, nil
+}
+
+// WriteFile writes the baseline to a file at given path, replacing it
+// if it exists.
+func (b *Baseline) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Write writes the baseline to w as JSON, with entries sorted by file,
+// word, and context.
+func (b *Baseline) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(file{version, b.Entries()})
+}
+
+// Add adds an entry to the baseline. If the entry is already known, its
+// count is added to the known count. A count of 0 is counted as 1.
+func (b *Baseline) Add(e Entry) {
+	if e.Count < 1 {
+		e.Count = 1
+	}
+
+	b.counts[e.key()] += e.Count
+}
+
+// Match returns true if an occurrence of the entry is known, and removes
+// it from the baseline, so that each known occurrence is matched once.
+func (b *Baseline) Match(e Entry) bool {
+	k := e.key()
+	if b.counts[k] == 0 {
+		return false
+	}
+
+	if b.counts[k]--; b.counts[k] == 0 {
+		delete(b.counts, k)
+	}
+
+	return true
+}
+
+// Len returns the number of occurrences in the baseline.
+func (b *Baseline) Len() int {
+	n := 0
+	for _, count := range b.counts {
+		n += count
+	}
+
+	return n
+}
+
+// Entries returns entries of the baseline sorted by file, word, and
+// context. After matching, these are the entries that weren't found,
+// that is errors that were fixed.
+func (b *Baseline) Entries() []Entry {
+	entries := make([]Entry, 0, len(b.counts))
+	for k, count := range b.counts {
+		entries = append(entries, Entry{k.file, k.word, k.kind, k.context, count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.File != b.File:
+			return a.File < b.File
+		case a.Word != b.Word:
+			return a.Word < b.Word
+		case a.Context != b.Context:
+			return a.Context < b.Context
+		default:
+			return a.Kind < b.Kind
+		}
+	})
+
+	return entries
+}
+
+// key returns the entry's key.
+func (e Entry) key() key {
+	return key{e.File, e.Word, e.Kind, e.Context}
+}
+
+// Context returns the context of a word at line[start:end], that is the
+// word, preceded and followed by the words surrounding it in its line.
+func Context(line string, start, end int) string {
+	if start < 0 || end > len(line) || start > end {
+		return ""
+	}
+
+	context := []string{line[start:end]}
+	if before := strings.Fields(line[:start]); len(before) != 0 {
+		context = append([]string{before[len(before)-1]}, context...)
+	}
+
+	if after := strings.Fields(line[end:]); len(after) != 0 {
+		context = append(context, after[0])
+	}
+
+	return strings.Join(context, " ")
+}
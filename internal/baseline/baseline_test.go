@@ -0,0 +1,92 @@
+package baseline
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Test matching errors against baseline entries, and listing unmatched ones.
+func TestMatching(t *testing.T) {
+	b := New()
+	b.Add(Entry{File: "a.md", Word: "wrd", Kind: "unknown-word", Context: "a wrd here"})
+	b.Add(Entry{File: "a.md", Word: "wrd", Kind: "unknown-word", Context: "a wrd here"})
+	b.Add(Entry{File: "b.md", Word: "teh", Kind: "unknown-word", Context: "teh end", Count: 3})
+
+	if b.Len() != 5 {
+		t.Errorf("Expected 5 occurrences, found %d.", b.Len())
+	}
+
+	known := Entry{File: "a.md", Word: "wrd", Kind: "unknown-word", Context: "a wrd here"}
+	for i := 0; i < 2; i++ {
+		if !b.Match(known) {
+			t.Errorf("Expected occurrence %d of %+v to match.", i, known)
+		}
+	}
+
+	// Each occurrence is matched once
+	if b.Match(known) {
+		t.Errorf("Expected %+v not to match a third time.", known)
+	}
+
+	// Entries differing in any field don't match
+	for _, e := range []Entry{
+		{File: "c.md", Word: "teh", Kind: "unknown-word", Context: "teh end"},
+		{File: "b.md", Word: "teh", Kind: "bad-casing", Context: "teh end"},
+		{File: "b.md", Word: "teh", Kind: "unknown-word", Context: "at teh end"},
+	} {
+		if b.Match(e) {
+			t.Errorf("Expected %+v not to match.", e)
+		}
+	}
+
+	b.Match(Entry{File: "b.md", Word: "teh", Kind: "unknown-word", Context: "teh end"})
+
+	fixed := []Entry{{File: "b.md", Word: "teh", Kind: "unknown-word", Context: "teh end", Count: 2}}
+	if entries := b.Entries(); !reflect.DeepEqual(entries, fixed) {
+		t.Errorf("Expected remaining entries %+v, found %+v.", fixed, entries)
+	}
+}
+
+// Test writing a baseline, and reading the written file.
+func TestReadingAndWriting(t *testing.T) {
+	b := New()
+	b.Add(Entry{File: "b.md", Word: "teh", Kind: "unknown-word", Context: "teh end", Count: 2})
+	b.Add(Entry{File: "a.md", Word: "wrd", Kind: "unknown-word", Context: "a wrd"})
+
+	buf := new(bytes.Buffer)
+	if err := b.Write(buf); err != nil {
+		t.Fatalf("Writing failed: %v.", err)
+	}
+
+	read, err := Read(buf)
+	if err != nil {
+		t.Fatalf("Reading failed: %v.", err)
+	}
+
+	if !reflect.DeepEqual(read.Entries(), b.Entries()) {
+		t.Errorf("Expected entries %+v, found %+v.", b.Entries(), read.Entries())
+	}
+
+	if _, err := Read(bytes.NewBufferString(`{"version": 2, "entries": []}`)); err == nil {
+		t.Errorf("Expected an error reading an unsupported version.")
+	}
+}
+
+// Test extraction of the context of an error from its line.
+func TestContext(t *testing.T) {
+	line := "The  quick brwn fox."
+	for _, test := range []struct {
+		start, end int
+		expected   string
+	}{
+		{11, 15, "quick brwn fox."},
+		{0, 3, "The quick"},
+		{16, 19, "brwn fox ."},
+		{0, 30, ""},
+	} {
+		if found := Context(line, test.start, test.end); found != test.expected {
+			t.Errorf("Expected context %q, found %q.", test.expected, found)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package config implements loading of project-wide configuration
+// files, named .gocheck.yaml (or .gocheck.yml) and .gocheck.toml.
+//
+// Both formats are limited to what configuration needs: top-level keys
+// with string, and list of strings values, and a single level of nested
+// string tables.
+//
+//	# .gocheck.yaml
+//	dictionaries:
+//	  - words.txt
+//	ignore: [gocheck, stdin]
+//	ignore-patterns:
+//	  - '^0x[0-9a-f]+$'
+//	include: ["**/*.md", "**/*.go"]
+//	exclude: [vendor]
+//	modes:
+//	  "*.md": markdown
+//	  "*.go": code
+//	rules: [repeated, capitalization]
+//	format: text
+//
+//	# .gocheck.toml
+//	dictionaries = ["words.txt"]
+//	ignore = ["gocheck", "stdin"]
+//	rules = ["repeated"]
+//
+//	[modes]
+//	"*.md" = "markdown"
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Names of configuration files, in order of precedence.
+var Names = []string{".gocheck.yaml", ".gocheck.yml", ".gocheck.toml"}
+
+// Config is a project-wide configuration.
+type Config struct {
+	Path           string   // Path of the configuration file
+	Dictionaries   []string // Paths of dictionaries, relative to the file's directory if not absolute
+	Ignore         []string // Ignored words
+	IgnorePatterns []string // Regular expressions of ignored words
+	Include        []string // Globs of files to check, all files if empty
+	Exclude        []string // Globs of files and directories not to check
+	Modes          []Mode   // File-type modes, the first matching mode is used
+	Rules          []string // Names of enabled rules
+	Format         string   // Output format
+}
+
+// Mode is the file-type mode used for files matching a glob.
+type Mode struct {
+	Glob string // Glob of files, see Match
+	Mode string // Name of the mode
+}
+
+// entry is a top-level key of a configuration file, and its value.
+// Only one of scalar, list, and table is set.
+type entry struct {
+	line   int
+	key    string
+	scalar *string
+	list   []string
+	table  []pair
+}
+
+// pair is a key and a value of a nested table.
+type pair struct {
+	key   string
+	value string
+}
+
+// Find returns the path of the configuration file found in dir or its
+// closest ancestor, or an empty string if there's none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range Names {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// LoadFile loads the configuration file at given path, its format is
+// decided by its extension. Returns an error if reading, or parsing
+// fails.
+func LoadFile(filePath string) (*Config, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []entry
+	switch filepath.Ext(filePath) {
+	case ".yaml", ".yml":
+		entries, err = parseYAML(file)
+	case ".toml":
+		entries, err = parseTOML(file)
+	default:
+		return nil, fmt.Errorf("%s: unknown configuration format", filePath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+
+	config, err := build(entries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+
+	config.Path = filePath
+	for i, dictionary := range config.Dictionaries {
+		if !filepath.IsAbs(dictionary) {
+			config.Dictionaries[i] = filepath.Join(config.Dir(), dictionary)
+		}
+	}
+
+	return config, nil
+}
+
+// build creates a Config from parsed entries.
+func build(entries []entry) (*Config, error) {
+	config := new(Config)
+	for _, e := range entries {
+		var err error
+		switch e.key {
+		case "dictionaries":
+			config.Dictionaries, err = e.strings()
+		case "ignore":
+			config.Ignore, err = e.strings()
+		case "ignore-patterns":
+			config.IgnorePatterns, err = e.strings()
+		case "include":
+			config.Include, err = e.strings()
+		case "exclude":
+			config.Exclude, err = e.strings()
+		case "rules":
+			config.Rules, err = e.strings()
+		case "format":
+			if e.scalar == nil {
+				err = fmt.Errorf("expected a string")
+			} else {
+				config.Format = *e.scalar
+			}
+		case "modes":
+			if e.table == nil && (e.scalar == nil || *e.scalar != "") {
+				err = fmt.Errorf("expected a table of globs and modes")
+			}
+
+			for _, p := range e.table {
+				config.Modes = append(config.Modes, Mode{p.key, p.value})
+			}
+		default:
+			err = fmt.Errorf("unknown key")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %q: %v", e.line, e.key, err)
+		}
+	}
+
+	return config, nil
+}
+
+// strings returns the entry's value as a list of strings. A scalar is
+// a list of one string.
+func (e entry) strings() ([]string, error) {
+	switch {
+	case e.table != nil:
+		return nil, fmt.Errorf("expected a list of strings")
+	case e.scalar != nil && *e.scalar == "":
+		return nil, nil
+	case e.scalar != nil:
+		return []string{*e.scalar}, nil
+	default:
+		return e.list, nil
+	}
+}
+
+// Dir returns the directory of the configuration file, globs match
+// paths relative to it.
+func (c *Config) Dir() string {
+	if c.Path == "" {
+		return "."
+	}
+
+	return filepath.Dir(c.Path)
+}
+
+// ModeOf returns the mode of the file at given slash-separated path,
+// relative to the configuration file's directory, or an empty string
+// if no mode matches it.
+func (c *Config) ModeOf(name string) string {
+	for _, mode := range c.Modes {
+		if Match(mode.Glob, name) {
+			return mode.Mode
+		}
+	}
+
+	return ""
+}
+
+// Match returns true if the slash-separated path name matches glob.
+// Globs use the syntax of path.Match, and "**" matches any number of
+// directories. A glob without a slash matches the last element of the
+// path, such as "*.md" or "vendor".
+func Match(glob, name string) bool {
+	if !strings.Contains(glob, "/") {
+		ok, _ := path.Match(glob, path.Base(name))
+		return ok
+	}
+
+	return matchParts(strings.Split(strings.TrimPrefix(glob, "/"), "/"), strings.Split(name, "/"))
+}
+
+// matchParts, recursively, matches elements of a path against elements
+// of a glob.
+func matchParts(glob, name []string) bool {
+	for len(glob) != 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchParts(glob[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(glob[0], name[0]); !ok {
+			return false
+		}
+
+		glob, name = glob[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Expected result of parsing yamlConfig and tomlConfig.
+var expected = &Config{
+	Dictionaries:   []string{"words.txt", "/usr/share/dict/words"},
+	Ignore:         []string{"gocheck", "stdin"},
+	IgnorePatterns: []string{`^0x[0-9a-f]+$`, `\d+`},
+	Include:        []string{"**/*.md", "**/*.go"},
+	Exclude:        []string{"vendor"},
+	Modes: []Mode{
+		{"*.md", "markdown"},
+		{"docs/**/*.txt", "text"},
+	},
+	Rules:  []string{"repeated"},
+	Format: "json",
+}
+
+const yamlConfig = `# Project configuration
+dictionaries:
+  - words.txt
+  - "/usr/share/dict/words"
+ignore: [gocheck, 'stdin']   # Inline comment
+ignore-patterns:
+  - '^0x[0-9a-f]+$'
+  - "\\d+"
+include: ["**/*.md", "**/*.go"]
+exclude: vendor
+modes:
+  "*.md": markdown
+  docs/**/*.txt: text
+rules: [repeated]
+format: json
+`
+
+const tomlConfig = `# Project configuration
+dictionaries = ["words.txt", "/usr/share/dict/words"]
+ignore = ["gocheck", 'stdin']   # Inline comment
+ignore-patterns = [
+	'^0x[0-9a-f]+$',
+	"\\d+",
+]
+include = ["**/*.md", "**/*.go"]
+exclude = "vendor"
+rules = ["repeated"]
+format = "json"
+
+[modes]
+"*.md" = "markdown"
+"docs/**/*.txt" = "text"
+`
+
+// Test parsing YAML and TOML configuration files.
+func TestParsing(t *testing.T) {
+	for name, parse := range map[string]func(string) ([]entry, error){
+		"yaml": func(s string) ([]entry, error) { return parseYAML(strings.NewReader(s)) },
+		"toml": func(s string) ([]entry, error) { return parseTOML(strings.NewReader(s)) },
+	} {
+		text := yamlConfig
+		if name == "toml" {
+			text = tomlConfig
+		}
+
+		entries, err := parse(text)
+		if err != nil {
+			t.Fatalf("Parsing %s failed: %v.", name, err)
+		}
+
+		config, err := build(entries)
+		if err != nil {
+			t.Fatalf("Building %s failed: %v.", name, err)
+		}
+
+		if !reflect.DeepEqual(config, expected) {
+			t.Errorf("Expected %s configuration %+v, found %+v.", name, expected, config)
+		}
+	}
+}
+
+// Test errors parsing invalid configuration files.
+func TestParsingErrors(t *testing.T) {
+	for _, text := range []string{
+		"unknown: value\n",
+		"  - indented\n",
+		"ignore: [a, b\n",
+		"ignore:\n  - a\n  b: c\n",
+		"format: [a]\n",
+		"modes: markdown\n",
+		"ignore: \"unterminated\n",
+	} {
+		entries, err := parseYAML(strings.NewReader(text))
+		if err == nil {
+			_, err = build(entries)
+		}
+
+		if err == nil {
+			t.Errorf("Expected an error parsing %q.", text)
+		}
+	}
+
+	for _, text := range []string{
+		"ignore = [\"a\",\n",
+		"ignore\n",
+		"[modes]\n\"*.md\" = [\"a\"\n",
+	} {
+		if _, err := parseTOML(strings.NewReader(text)); err == nil {
+			t.Errorf("Expected an error parsing %q.", text)
+		}
+	}
+}
+
+// Test finding a configuration file in a parent directory, and loading it.
+func TestFindAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gocheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	nested := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, ".gocheck.toml")
+	if err := ioutil.WriteFile(path, []byte(tomlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := Find(nested)
+	if err != nil || found != path {
+		t.Fatalf("Expected to find %s, found %q (%v).", path, found, err)
+	}
+
+	config, err := LoadFile(found)
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	// Relative dictionaries are relative to the configuration file
+	dictionaries := []string{filepath.Join(dir, "words.txt"), "/usr/share/dict/words"}
+	if !reflect.DeepEqual(config.Dictionaries, dictionaries) {
+		t.Errorf("Expected dictionaries %v, found %v.", dictionaries, config.Dictionaries)
+	}
+
+	if mode := config.ModeOf("docs/a/b.txt"); mode != "text" {
+		t.Errorf("Expected mode \"text\", found %q.", mode)
+	}
+}
+
+// Test matching of paths against globs.
+func TestMatch(t *testing.T) {
+	for _, test := range []struct {
+		glob, name string
+		expected   bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", true},
+		{"*.md", "docs/guide.txt", false},
+		{"vendor", "a/vendor", true},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/gocheck/main.go", true},
+		{"cmd/*.go", "cmd/gocheck/main.go", false},
+		{"cmd/**", "cmd/gocheck/main.go", true},
+		{"/docs/*.md", "docs/a.md", true},
+		{"docs/**/b.md", "docs/b.md", true},
+		{"docs/**/b.md", "src/docs/b.md", false},
+	} {
+		if found := Match(test.glob, test.name); found != test.expected {
+			t.Errorf("Expected Match(%q, %q) to be %t, found %t.", test.glob, test.name, test.expected, found)
+		}
+	}
+}
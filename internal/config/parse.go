@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses a YAML configuration. Supported are top-level keys
+// with a scalar, a flow list ("[a, b]"), a block list ("- a" lines), or
+// a block mapping ("a: b" lines) value.
+func parseYAML(r io.Reader) ([]entry, error) {
+	var entries []entry
+
+	scanner := bufio.NewScanner(r)
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimRight(stripComment(scanner.Text()), " \t")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+
+		text := strings.TrimSpace(line)
+		if line[0] != ' ' && line[0] != '\t' { // Top-level key
+			key, value, err := splitPair(text, ":")
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			e := entry{line: row, key: key}
+			if err := e.setValue(value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			entries = append(entries, e)
+			continue
+		}
+
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("line %d: unexpected indentation", row)
+		}
+
+		e := &entries[len(entries)-1]
+		switch {
+		case e.scalar != nil && *e.scalar != "":
+			return nil, fmt.Errorf("line %d: unexpected indentation", row)
+		case text == "-" || strings.HasPrefix(text, "- "):
+			if e.table != nil {
+				return nil, fmt.Errorf("line %d: expected a key and a value", row)
+			}
+
+			item, err := unquote(strings.TrimSpace(text[1:]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			e.scalar, e.list = nil, append(e.list, item)
+		default:
+			if e.list != nil {
+				return nil, fmt.Errorf("line %d: expected a list item", row)
+			}
+
+			key, value, err := splitPair(text, ":")
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			if value, err = unquote(value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			e.scalar, e.table = nil, append(e.table, pair{key, value})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseTOML parses a TOML configuration. Supported are keys with string
+// and array of strings values, and tables of strings.
+func parseTOML(r io.Reader) ([]entry, error) {
+	var (
+		entries []entry
+		table   *entry
+		pending string // Text of an array spanning multiple lines
+		start   int    // Line at which pending starts
+	)
+
+	scanner := bufio.NewScanner(r)
+	for row := 1; scanner.Scan(); row++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if pending != "" {
+			text, pending = pending+" "+text, ""
+		} else {
+			start = row
+		}
+
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") && !strings.Contains(text, "=") {
+			key, err := unquote(strings.TrimSpace(text[1 : len(text)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			entries = append(entries, entry{line: row, key: key, table: []pair{}})
+			table = &entries[len(entries)-1]
+			continue
+		}
+
+		key, value, err := splitPair(text, "=")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", start, err)
+		}
+
+		if strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") {
+			pending = text
+			continue
+		}
+
+		if table != nil {
+			if value, err = unquote(value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", start, err)
+			}
+
+			table.table = append(table.table, pair{key, value})
+			continue
+		}
+
+		e := entry{line: start, key: key}
+		if err := e.setValue(value); err != nil {
+			return nil, fmt.Errorf("line %d: %v", start, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if pending != "" {
+		return nil, fmt.Errorf("line %d: unterminated array", start)
+	}
+
+	return entries, nil
+}
+
+// setValue sets the entry's value to a scalar, or a flow list.
+func (e *entry) setValue(value string) error {
+	if !strings.HasPrefix(value, "[") {
+		scalar, err := unquote(value)
+		if err != nil {
+			return err
+		}
+
+		e.scalar = &scalar
+		return nil
+	}
+
+	if !strings.HasSuffix(value, "]") {
+		return fmt.Errorf("unterminated list")
+	}
+
+	e.list = []string{}
+	for _, item := range splitList(value[1 : len(value)-1]) {
+		item, err := unquote(item)
+		if err != nil {
+			return err
+		}
+
+		e.list = append(e.list, item)
+	}
+
+	return nil
+}
+
+// splitPair splits text into a key and a value at the first separator
+// outside of quotes.
+func splitPair(text, sep string) (string, string, error) {
+	i := indexOutside(text, sep)
+	if i < 0 {
+		return "", "", fmt.Errorf("expected a key and a value")
+	}
+
+	key, err := unquote(strings.TrimSpace(text[:i]))
+	if err != nil {
+		return "", "", err
+	}
+
+	if key == "" {
+		return "", "", fmt.Errorf("expected a key")
+	}
+
+	return key, strings.TrimSpace(text[i+len(sep):]), nil
+}
+
+// splitList splits the items of a flow list at commas outside of
+// quotes. Empty items are dropped.
+func splitList(text string) []string {
+	var items []string
+	for {
+		i := indexOutside(text, ",")
+		if i < 0 {
+			break
+		}
+
+		if item := strings.TrimSpace(text[:i]); item != "" {
+			items = append(items, item)
+		}
+
+		text = text[i+1:]
+	}
+
+	if item := strings.TrimSpace(text); item != "" {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// stripComment removes a comment starting with "#" outside of quotes.
+// In YAML and TOML, a comment must start a line or follow whitespace.
+func stripComment(line string) string {
+	for i := indexOutside(line, "#"); i >= 0; {
+		if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+			return line[:i]
+		}
+
+		next := indexOutside(line[i+1:], "#")
+		if next < 0 {
+			break
+		}
+
+		i += 1 + next
+	}
+
+	return line
+}
+
+// indexOutside returns the index of the first occurrence of sep in text
+// outside of single or double quotes, or -1 if there's none.
+func indexOutside(text, sep string) int {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote != 0:
+			if text[i] == quote {
+				quote = 0
+			}
+		case text[i] == '"' || text[i] == '\'':
+			quote = text[i]
+		case strings.HasPrefix(text[i:], sep):
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unquote removes quotes surrounding a scalar. Double quoted scalars may
+// contain Go escape sequences, single quoted scalars are literal.
+func unquote(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '"' && s[0] != '\'') {
+		return s, nil
+	}
+
+	if s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+
+	return unquoted, nil
+}
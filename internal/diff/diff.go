@@ -0,0 +1,143 @@
+// Package diff implements parsing of unified diffs, such as the output
+// of "git diff", to find lines added or modified in each file.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// File is a file changed in a diff.
+type File struct {
+	Path  string       // Path of the new file, without a "b/" prefix
+	Added map[int]bool // Rows of added or modified lines in the new file, starting at 0
+}
+
+// Parse parses a unified diff read from r. Returns changed files in
+// order of appearance, deleted files are skipped.
+func Parse(r io.Reader) ([]File, error) {
+	var (
+		files   []File
+		current *File
+		row     int // Row of the next line in the new file
+		oldLeft int // Lines of the current hunk left in the old file
+		newLeft int // Lines of the current hunk left in the new file
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 { // Inside a hunk
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if current != nil {
+					current.Added[row] = true
+				}
+
+				row++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, " ") || line == "":
+				row++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, "\\"): // "\ No newline at end of file"
+			default:
+				return nil, fmt.Errorf("line %d: unexpected line in hunk", n)
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path := newPath(line[4:])
+			if path == "" {
+				current = nil
+				continue
+			}
+
+			files = append(files, File{path, make(map[int]bool)})
+			current = &files[len(files)-1]
+		case strings.HasPrefix(line, "@@ "):
+			start, old, count, err := parseHunk(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+
+			row, oldLeft, newLeft = start-1, old, count
+			if count == 0 {
+				row = start // An empty range starts after the given line
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// newPath returns the path in a "+++" line, without a "b/" prefix, or
+// an empty string if the file was deleted.
+func newPath(text string) string {
+	if i := strings.IndexByte(text, '\t'); i >= 0 { // Timestamp
+		text = text[:i]
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "/dev/null" {
+		return ""
+	}
+
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	return strings.TrimPrefix(text, "b/")
+}
+
+// parseHunk parses a hunk header, "@@ -l,s +l,s @@". Returns the first
+// line, starting at 1, of the hunk in the new file, and the number of
+// lines of the hunk in the old and the new file.
+func parseHunk(line string) (start, old, count int, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header %q", line)
+	}
+
+	if _, old, err = parseRange(fields[1][1:]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if start, count, err = parseRange(fields[2][1:]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return start, old, count, nil
+}
+
+// parseRange parses a range of a hunk header, "l,s" or "l" in which
+// case s is 1.
+func parseRange(text string) (start, count int, err error) {
+	parts := strings.SplitN(text, ",", 2)
+	if start, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q", text)
+	}
+
+	count = 1
+	if len(parts) == 2 {
+		if count, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, fmt.Errorf("invalid range %q", text)
+		}
+	}
+
+	return start, count, nil
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const gitDiff = `diff --git a/docs/a.md b/docs/a.md
+index 3b18e51..a9e1f2c 100644
+--- a/docs/a.md
++++ b/docs/a.md
+@@ -1,4 +1,4 @@
+ first line
+-second line
++second lne
++new line
+ third line
+--- not a header
+@@ -10,0 +12,2 @@ context
++added at 11
++added at 12
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-gone
+-also gone
+diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt	2020-01-01 00:00:00
+@@ -0,0 +1 @@
++only line
+\ No newline at end of file
+`
+
+// Test parsing changed rows of files from a git diff.
+func TestParse(t *testing.T) {
+	files, err := Parse(strings.NewReader(gitDiff))
+	if err != nil {
+		t.Fatalf("Parsing failed: %v.", err)
+	}
+
+	expected := []File{
+		{"docs/a.md", map[int]bool{1: true, 2: true, 11: true, 12: true}},
+		{"new.txt", map[int]bool{0: true}},
+	}
+
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %v, found %v.", expected, files)
+	}
+}
+
+// Test parsing a diff without context lines.
+func TestParseZeroContext(t *testing.T) {
+	text := "--- a/a.txt\n+++ b/a.txt\n@@ -3 +3 @@\n-old\n+new\n@@ -5,0 +6 @@\n+inserted\n@@ -8 +8,0 @@\n-removed\n"
+	files, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Parsing failed: %v.", err)
+	}
+
+	expected := []File{{"a.txt", map[int]bool{2: true, 5: true}}}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %v, found %v.", expected, files)
+	}
+}
+
+// Test errors parsing invalid diffs.
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"+++ b/a.txt\n@@ -1 +x @@\n",
+		"+++ b/a.txt\n@@ -1,2 +1,2 @@\n line\n?invalid\n",
+	} {
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("Expected an error parsing %q.", text)
+		}
+	}
+}
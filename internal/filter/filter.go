@@ -0,0 +1,187 @@
+// Package filter implements file-type modes, which blank out parts of
+// a file that shouldn't be spell-checked, such as code in Markdown
+// files, or code outside of comments in source files.
+//
+// Blanked parts are replaced with spaces, so lines, and offsets of
+// remaining text, don't change.
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Names of supported modes.
+const (
+	Text     = "text"     // Check all text
+	Markdown = "markdown" // Skip code blocks, inline code, URLs, and HTML tags
+	Code     = "code"     // Check only comments ("//", "#", and "/* */")
+)
+
+// Modes is a list of names of all supported modes.
+var Modes = []string{Text, Markdown, Code}
+
+// Filter blanks out parts of lines of a file that shouldn't be checked.
+// A Filter keeps state between lines, so a new Filter is needed for
+// each file.
+type Filter interface {
+	Line(text string) string
+}
+
+// New returns a new Filter of the mode with the given name.
+func New(mode string) (Filter, error) {
+	switch mode {
+	case Text, "":
+		return text{}, nil
+	case Markdown:
+		return new(markdown), nil
+	case Code:
+		return new(code), nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+}
+
+// Apply filters all lines of text using the mode with the given name.
+func Apply(mode, text string) (string, error) {
+	f, err := New(mode)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = f.Line(line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// reader filters lines read from an underlying reader.
+type reader struct {
+	filter Filter
+	r      *bufio.Reader
+	buf    string // Filtered text that wasn't read yet
+	err    error  // Error reading the underlying reader
+}
+
+// NewReader returns a reader of text read from r, with each line
+// filtered using f as it's read. Only one line is kept in memory.
+func NewReader(f Filter, r io.Reader) io.Reader {
+	return &reader{filter: f, r: bufio.NewReader(r)}
+}
+
+// Read reads filtered text into p.
+func (r *reader) Read(p []byte) (int, error) {
+	for r.buf == "" {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		var line string
+		line, r.err = r.r.ReadString('\n')
+		if text := strings.TrimSuffix(line, "\n"); line != "" {
+			r.buf = r.filter.Line(text) + line[len(text):]
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// blank returns a string of spaces of the same length as s.
+func blank(s string) string {
+	return strings.Repeat(" ", len(s))
+}
+
+// text is a filter that keeps all text.
+type text struct{}
+
+// Line returns the line as is.
+func (text) Line(line string) string {
+	return line
+}
+
+// Patterns of Markdown elements that aren't checked.
+var (
+	fence      = regexp.MustCompile("^ {0,3}(```|~~~)")
+	inlineCode = regexp.MustCompile("`[^`]*`")
+	linkTarget = regexp.MustCompile(`\]\([^)]*\)`)
+	htmlTag    = regexp.MustCompile(`<[^>\s][^>]*>`)
+	url        = regexp.MustCompile(`[a-z]+://\S+`)
+)
+
+// markdown is a filter of Markdown files.
+type markdown struct {
+	fence string // Delimiter of the current code block, empty if outside
+}
+
+// Line blanks out code blocks, inline code, link targets, HTML tags,
+// and URLs.
+func (m *markdown) Line(line string) string {
+	if m.fence != "" {
+		if strings.HasPrefix(strings.TrimSpace(line), m.fence) {
+			m.fence = ""
+		}
+
+		return blank(line)
+	}
+
+	if match := fence.FindStringSubmatch(line); match != nil {
+		m.fence = match[1]
+		return blank(line)
+	}
+
+	for _, pattern := range []*regexp.Regexp{inlineCode, linkTarget, htmlTag, url} {
+		line = pattern.ReplaceAllStringFunc(line, blank)
+	}
+
+	return line
+}
+
+// code is a filter of source files, it keeps only comments.
+type code struct {
+	block bool // True if inside a block comment
+}
+
+// Line blanks out everything but comments.
+func (c *code) Line(line string) string {
+	out := []byte(blank(line))
+
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch {
+		case c.block:
+			if strings.HasPrefix(line[i:], "*/") {
+				c.block = false
+				i++
+			} else {
+				out[i] = line[i]
+			}
+		case quote != 0:
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == quote {
+				quote = 0
+			}
+		case line[i] == '"' || line[i] == '\'' || line[i] == '`':
+			quote = line[i]
+		case strings.HasPrefix(line[i:], "/*"):
+			c.block = true
+			i++
+		case strings.HasPrefix(line[i:], "//"):
+			copy(out[i+2:], line[i+2:])
+			return string(out)
+		case line[i] == '#':
+			copy(out[i+1:], line[i+1:])
+			return string(out)
+		}
+	}
+
+	return string(out)
+}
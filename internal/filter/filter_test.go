@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Test filtering of code, links, and HTML in Markdown.
+func TestMarkdown(t *testing.T) {
+	text := "Some `code` and a [link](http://exmple.com).\n" +
+		"```go\n" +
+		"fmt.Printn(\"x\")\n" +
+		"```\n" +
+		"Visit https://exmple.com <br> now."
+
+	expected := []string{
+		"Some        and a [link                    .",
+		"",
+		"",
+		"",
+		"Visit                         now.",
+	}
+
+	checkFilter(t, Markdown, text, expected)
+}
+
+// Test filtering of source code, keeping only comments.
+func TestCode(t *testing.T) {
+	text := "x := \"// not a comment\" // A comment\n" +
+		"/* Block\n" +
+		"comment */ y = '#'\n" +
+		"# Script comment"
+
+	expected := []string{
+		"                           A comment",
+		"   Block",
+		"comment",
+		"  Script comment",
+	}
+
+	checkFilter(t, Code, text, expected)
+}
+
+// Test text mode keeping lines unchanged, and unknown modes.
+func TestText(t *testing.T) {
+	text := "Some `code`\n// comment"
+	checkFilter(t, Text, text, strings.Split(text, "\n"))
+
+	if _, err := New("unknown"); err == nil {
+		t.Errorf("Expected an error creating an unknown mode.")
+	}
+}
+
+// Test filtering text line by line while reading it.
+func TestReader(t *testing.T) {
+	text := "Some `code`\n```\nx := 1\n```\nend"
+	f, err := New(Markdown)
+	if err != nil {
+		t.Fatalf("Creating a filter failed: %v.", err)
+	}
+
+	filtered, err := ioutil.ReadAll(NewReader(f, strings.NewReader(text)))
+	if err != nil {
+		t.Fatalf("Reading failed: %v.", err)
+	}
+
+	expected, _ := Apply(Markdown, text)
+	if string(filtered) != expected {
+		t.Errorf("Expected %q, found %q.", expected, filtered)
+	}
+}
+
+// Check that filtering text using mode results in expected lines,
+// ignoring trailing spaces, and that lengths of lines are kept.
+func checkFilter(t *testing.T, mode, text string, expected []string) {
+	filtered, err := Apply(mode, text)
+	if err != nil {
+		t.Fatalf("Filtering failed: %v.", err)
+	}
+
+	lines := strings.Split(filtered, "\n")
+	original := strings.Split(text, "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, found %d.", len(expected), len(lines))
+	}
+
+	for i, line := range lines {
+		if len(line) != len(original[i]) {
+			t.Errorf("Expected line %d to have length %d, found %d.", i, len(original[i]), len(line))
+		}
+
+		if strings.TrimRight(line, " ") != expected[i] {
+			t.Errorf("Expected line %d to be %q, found %q.", i, expected[i], line)
+		}
+	}
+}
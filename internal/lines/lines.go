@@ -0,0 +1,77 @@
+// Package lines implements reading of text lines of any length.
+package lines
+
+import (
+	"bufio"
+	"io"
+)
+
+// Reader reads lines of text from an io.Reader. Unlike bufio.Scanner,
+// Reader is not limited by a maximum token size, lines can be of any
+// length unless a maximum is set.
+type Reader struct {
+	r   *bufio.Reader
+	max int // Maximum length of a line, 0 for no limit
+}
+
+// NewReader returns a Reader that reads from r. Lines longer than max
+// bytes are discarded, a max of 0 means there's no limit.
+func NewReader(r io.Reader, max int) *Reader {
+	return &Reader{bufio.NewReader(r), max}
+}
+
+// Next returns the next line, without its line ending. long is true
+// if the line is longer than the maximum length, in which case the
+// line is discarded and an empty string is returned. io.EOF is
+// returned when there are no more lines.
+func (r *Reader) Next() (line string, long bool, err error) {
+	var buf []byte
+
+	read := 0
+	for {
+		chunk, err := r.r.ReadSlice('\n')
+		read += len(chunk)
+
+		// Keep at most max bytes, and a line ending
+		if !long {
+			buf = append(buf, chunk...)
+			if r.max > 0 && len(buf) > r.max+2 {
+				long, buf = true, nil
+			}
+		}
+
+		switch err {
+		case nil:
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			if read == 0 {
+				return "", false, io.EOF
+			}
+		default:
+			return "", false, err
+		}
+
+		break
+	}
+
+	buf = dropEnding(buf)
+	if r.max > 0 && len(buf) > r.max {
+		long, buf = true, nil
+	}
+
+	return string(buf), long, nil
+}
+
+// dropEnding removes a trailing "\n" or "\r\n" from a line.
+func dropEnding(line []byte) []byte {
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+
+	return line
+}
@@ -0,0 +1,76 @@
+package lines
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// readAll returns all lines read from text, and rows of long lines.
+func readAll(t *testing.T, text string, max int) ([]string, []int) {
+	r := NewReader(strings.NewReader(text), max)
+
+	var lines []string
+	var long []int
+	for row := 0; ; row++ {
+		line, isLong, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Reading failed: %v.", err)
+		}
+
+		if isLong {
+			long = append(long, row)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, long
+}
+
+// Test reading lines with different line endings.
+func TestNext(t *testing.T) {
+	lines, long := readAll(t, "one\ntwo\r\n\nthree", 0)
+
+	expected := []string{"one", "two", "", "three"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, found %q.", expected, lines)
+	}
+
+	if len(long) != 0 {
+		t.Errorf("Found long lines without a maximum: %v.", long)
+	}
+}
+
+// Test reading lines longer than bufio's buffer.
+func TestNextLongLine(t *testing.T) {
+	longLine := strings.Repeat("word ", 100000)
+	lines, _ := readAll(t, "short\n"+longLine+"\nshort\n", 0)
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, found %d.", len(lines))
+	}
+
+	if lines[1] != longLine {
+		t.Errorf("Long line was not read correctly, read %d bytes.", len(lines[1]))
+	}
+}
+
+// Test discarding lines longer than a maximum.
+func TestNextMaxLength(t *testing.T) {
+	text := "short\n" + strings.Repeat("x", 10000) + "\nexactly10!\r\nthis is too long"
+	lines, long := readAll(t, text, 10)
+
+	expected := []string{"short", "", "exactly10!", ""}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, found %q.", expected, lines)
+	}
+
+	if len(long) != 2 || long[0] != 1 || long[1] != 3 {
+		t.Errorf("Expected long lines at rows [1 3], found %v.", long)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package personal
+
+import "os"
+
+// lock doesn't lock f, as file locks aren't supported on this platform,
+// so concurrent appends aren't synchronized.
+func lock(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package personal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// lockFileEx is LockFileEx of kernel32.dll, which isn't in package syscall.
+var lockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+
+// lockfileExclusiveLock is the LOCKFILE_EXCLUSIVE_LOCK flag of LockFileEx.
+const lockfileExclusiveLock = 0x2
+
+// lock blocks until it acquires an exclusive lock of f, released when f
+// is closed.
+func lock(f *os.File) error {
+	var overlapped syscall.Overlapped
+	ok, _, err := lockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if ok == 0 {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package personal implements the personal dictionary, a list of words
+// accepted by a user in addition to dictionaries, kept in a file under
+// the user's configuration directory.
+//
+// Words are appended to the file while holding an exclusive lock of it,
+// so multiple processes can add words at the same time. The lock is
+// released by the operating system when the file is closed, including
+// when a process crashes, so it can't be left behind.
+package personal
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// Path returns the default path of the personal dictionary,
+// gocheck/words.txt under the user's configuration directory.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gocheck", "words.txt"), nil
+}
+
+// Load loads the personal dictionary at given path. Returns an empty
+// trie if the file doesn't exist.
+func Load(path string) (*loader.Node, error) {
+	root, err := loader.LoadFileOptions(path, loader.Options{})
+	if os.IsNotExist(err) {
+		return new(loader.Node), nil
+	}
+
+	return root, err
+}
+
+// Add appends words that aren't already known to the personal dictionary
+// at given path, creating it and its directory if needed. Returns the
+// number of words added, and an error if a word is invalid, that is empty
+// or containing spaces or bytes other than printable ASCII, which are
+// skipped when loading dictionaries.
+func Add(path string, words []string) (int, error) {
+	for _, word := range words {
+		if !valid(word) {
+			return 0, fmt.Errorf("invalid word %q", word)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := lock(f); err != nil {
+		return 0, fmt.Errorf("%s: %v", path, err)
+	}
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+
+	known, err := loader.LoadReader(bytes.NewReader(content), loader.Options{})
+	if err != nil {
+		return 0, err
+	}
+
+	buf := new(bytes.Buffer)
+	if len(content) != 0 && content[len(content)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	added := 0
+	for _, word := range words {
+		if !known.Contains(word) {
+			loader.LoadWord(known, word)
+			buf.WriteString(word + "\n")
+			added++
+		}
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return added, f.Close()
+}
+
+// valid returns true if word can be written as a line of a dictionary.
+func valid(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if word[i] <= ' ' || word[i] >= loader.FirstPrintableASCII+loader.PrintableASCII {
+			return false
+		}
+	}
+
+	return word != ""
+}
@@ -0,0 +1,95 @@
+package personal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test adding words to a personal dictionary.
+func TestAdding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "personal")
+	if err != nil {
+		t.Fatalf("Creating a directory failed: %v.", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "gocheck", "words.txt")
+	root, err := Load(path)
+	if err != nil {
+		t.Fatalf("Loading a missing dictionary failed: %v.", err)
+	}
+
+	if root.Len() != 0 {
+		t.Errorf("Expected a missing dictionary to be empty, found %d words.", root.Len())
+	}
+
+	if added, err := Add(path, []string{"gocheck", "iOS", "gocheck"}); err != nil || added != 2 {
+		t.Errorf("Expected 2 words added, found %d, error %v.", added, err)
+	}
+
+	if added, err := Add(path, []string{"iOS"}); err != nil || added != 0 {
+		t.Errorf("Expected known words not to be added, found %d added, error %v.", added, err)
+	}
+
+	for _, word := range []string{"", "two words", "tab\tword", "café"} {
+		if _, err := Add(path, []string{word}); err == nil {
+			t.Errorf("Expected an error adding %q.", word)
+		}
+	}
+
+	root, err = Load(path)
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	if words := root.Words(); strings.Join(words, " ") != "gocheck iOS" {
+		t.Errorf("Expected words [gocheck iOS], found %v.", words)
+	}
+}
+
+// Test adding words to a personal dictionary concurrently.
+func TestConcurrentAdding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "personal")
+	if err != nil {
+		t.Fatalf("Creating a directory failed: %v.", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A file without a trailing newline is appended to on a new line
+	path := filepath.Join(dir, "words.txt")
+	if err := ioutil.WriteFile(path, []byte("first"), 0644); err != nil {
+		t.Fatalf("Writing failed: %v.", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := Add(path, []string{fmt.Sprintf("word%d", i), "shared"}); err != nil {
+				t.Errorf("Adding failed: %v.", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading failed: %v.", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	sort.Strings(lines)
+
+	expected := "first shared word0 word1 word2 word3 word4 word5 word6 word7"
+	if strings.Join(lines, " ") != expected {
+		t.Errorf("Expected lines %q, found %q.", expected, strings.Join(lines, " "))
+	}
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// checkCase checks a word against a given Trie using the case rules
+// described in CheckWord, or regardless of case if ignoreCase is true.
+// correct is true if the word matches an entry, and known is true if
+// the word matches an entry case-insensitively.
+func checkCase(root *loader.Node, word string, ignoreCase bool) (correct, known bool) {
+	if root.Contains(word) {
+		return true, true
+	}
+
+	for _, entry := range lookupFold(root, word) {
+		if ignoreCase || matchesCase(entry, word) {
+			return true, true
+		}
+
+		known = true
+	}
+
+	return false, known
+}
+
+// matchesCase returns true if word is an accepted case variant of the
+// given dictionary entry.
+func matchesCase(entry, word string) bool {
+	switch {
+	case word == entry:
+		return true
+	case entry == strings.ToLower(entry):
+		return word == strings.ToUpper(entry[:1])+entry[1:] || word == strings.ToUpper(entry)
+	case isTitle(entry):
+		return word == strings.ToUpper(entry) || word == strings.ToLower(entry)
+	default:
+		return false
+	}
+}
+
+// isTitle returns true if word starts with an uppercase letter, and
+// contains no other uppercase letters.
+func isTitle(word string) bool {
+	return len(word) > 0 && isUpper(word[0]) && word[1:] == strings.ToLower(word[1:])
+}
+
+// isUpper returns true if b is an uppercase ASCII letter.
+func isUpper(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
+
+// lookupFold returns all entries of a given Trie that match word
+// case-insensitively.
+func lookupFold(root *loader.Node, word string) []string {
+	var entries []string
+	recFold(root, word, make([]byte, 0, len(word)), &entries)
+
+	return entries
+}
+
+// recFold, recursively, finds entries matching word case-insensitively.
+// prefix contains the characters of the entry matched so far.
+func recFold(node *loader.Node, word string, prefix []byte, entries *[]string) {
+	if len(prefix) == len(word) {
+		if node.IsWord() {
+			*entries = append(*entries, string(prefix))
+		}
+
+		return
+	}
+
+	b := word[len(prefix)]
+	for _, c := range foldCase(b) {
+		if next := node.Child(c); next != nil {
+			recFold(next, word, append(prefix, c), entries)
+		}
+	}
+}
+
+// foldCase returns the lowercase and uppercase variants of an ASCII
+// letter, or the character itself if it's not a letter.
+func foldCase(b byte) []byte {
+	switch {
+	case b >= 'a' && b <= 'z':
+		return []byte{b, b - 'a' + 'A'}
+	case isUpper(b):
+		return []byte{b, b - 'A' + 'a'}
+	default:
+		return []byte{b}
+	}
+}
@@ -7,46 +7,126 @@
 //
 // To check single words a Checker is not needed, you can simply use
 // the following
-//		if !checker.CheckWord(root, "Word") {
-//			// Do something ..
-// 		}
+//
+//	if !checker.CheckWord(root, "Word") {
+//		// Do something ..
+//	}
 //
 // To verify lists, lines, and text files, you need a Checker.
-//		c := checker.New()
-//		fileErrors, err := c.CheckFile(root, "path/to/file")
+//
+//	c := checker.New()
+//	fileErrors, err := c.CheckFile(root, "path/to/file")
+//
+// Large files can be streamed instead, errors are then handled as soon
+// as they are found, and checking can be cancelled using a context.
+//
+//	err := c.StreamFile(ctx, root, "path/to/file", func(e checker.SpellingError) error {
+//		// Do something ..
+//		return nil
+//	})
 //
 // Checkers provide several helpful options such as ignoring a certain
-// set of words when spell-checking, and detection of uppercase errors.
+// set of words when spell-checking, detection of uppercase errors, and
+// detection of repeated words and capitalization errors.
 package checker
 
 import (
-	"bufio"
-	"log"
+	"context"
+	"io"
 	"os"
+	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/sudo-sturbia/gocheck/v3/internal/lines"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/confusion"
 	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/phonetic"
 )
 
 // Checker is used to find spelling errors. Checker implements
 // several options when spell-checking such as ignored words, and
 // detection of incorrect usage of uppercase letters.
 type Checker struct {
-	ignored         map[string]bool // Map of words to ignore
-	ignoreUppercase bool            // Consider all given words to be lowercase
+	ignored         map[string]bool   // Map of words to ignore
+	ignoredPatterns []*regexp.Regexp  // Patterns of words to ignore
+	forbidden       map[string]bool   // Map of lowercase words to always report
+	severities      map[Kind]Severity // Severities set using SetSeverity
+	ignoreUppercase bool              // Consider all given words to be lowercase
+	workers         int               // Number of lines checked concurrently
+	maxLineLength   int               // Maximum length of a line, 0 for no limit
+	detectRepeated  bool              // Report consecutive duplicate words
+	capitalization  bool              // Report lowercase sentence starts and proper nouns
+	confusables     *confusion.Set    // Confusable words to check, nil if disabled
+	maxSuggestions  int               // Maximum number of suggestions
+	sounds          *phonetic.Index   // Index of sound-alike words, nil if disabled
+	keyboard        *Layout           // Keyboard layout used to weight edits, nil if unknown
+	compound        *CompoundOptions  // Options of compound word checking, nil if disabled
 }
 
 // SpellingError represents a spelling error found in a text file.
 type SpellingError struct {
-	Word string // Incorrectly spelled word.
-	Row  int    // Row containing the word.
-	Col  int    // Column containing the word.
+	Word     string   // Incorrectly spelled word.
+	Row      int      // Row containing the word.
+	Col      int      // Column containing the word.
+	Offset   int      // Byte offset of the word in its row.
+	Kind     Kind     // Kind of the error.
+	Severity Severity // Severity of the error's kind.
+	Fix      *Fix     // Edit that corrects the error, nil if none is known.
+}
+
+// Correction returns the corrected word, and true if the error's fix
+// only edits the word itself, without removing it.
+func (e SpellingError) Correction() (string, bool) {
+	f := e.Fix
+	if f == nil || f.Row != e.Row || f.Start < e.Offset || f.End > e.Offset+len(e.Word) {
+		return "", false
+	}
+
+	correction := e.Word[:f.Start-e.Offset] + f.Replacement + e.Word[f.End-e.Offset:]
+	return correction, correction != ""
+}
+
+// Fix is an edit that corrects a spelling error. It replaces bytes in
+// range [Start, End) of row Row with Replacement.
+type Fix struct {
+	Row         int    // Row to edit.
+	Start       int    // Byte offset of the first replaced byte.
+	End         int    // Byte offset after the last replaced byte.
+	Replacement string // Text to replace the range with.
+}
+
+// Apply returns the given line (the one at row f.Row) after editing
+// it using f.
+func (f *Fix) Apply(line string) string {
+	return line[:f.Start] + f.Replacement + line[f.End:]
 }
 
 // New returns pointer to a new, initialized Checker object.
 func New() *Checker {
-	return &Checker{make(map[string]bool), false}
+	return &Checker{
+		ignored:        make(map[string]bool),
+		forbidden:      make(map[string]bool),
+		severities:     make(map[Kind]Severity),
+		workers:        runtime.NumCPU(),
+		maxSuggestions: DefaultMaxSuggestions,
+	}
+}
+
+// SetWorkers sets the number of goroutines used to check lines of a
+// file concurrently. By default the number of CPUs is used, a value
+// smaller than 1 restores the default.
+func (c *Checker) SetWorkers(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+
+	c.workers = n
 }
 
 // Ignore adds a word to ignored words.
@@ -61,32 +141,122 @@ func (c *Checker) IgnoreList(words []string) {
 	}
 }
 
-// ClearIgnored clears Checker's ignored words list.
+// IgnorePattern ignores words matching a regular expression, such as
+// identifiers or hexadecimal numbers.
+func (c *Checker) IgnorePattern(pattern *regexp.Regexp) {
+	c.ignoredPatterns = append(c.ignoredPatterns, pattern)
+}
+
+// ClearIgnored clears Checker's ignored words list, and ignored
+// patterns.
 func (c *Checker) ClearIgnored(ignored bool) {
 	if ignored {
 		for i := range c.ignored {
 			delete(c.ignored, i)
 		}
+
+		c.ignoredPatterns = nil
 	}
 }
 
+// isIgnored returns true if word is ignored, or matches an ignored
+// pattern.
+func (c *Checker) isIgnored(word string) bool {
+	if c.ignored[word] {
+		return true
+	}
+
+	for _, pattern := range c.ignoredPatterns {
+		if pattern.MatchString(word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Forbid adds a word to forbidden words. Forbidden words are reported
+// as ForbiddenWord errors, regardless of their case, even if they exist
+// in the dictionary or are ignored.
+func (c *Checker) Forbid(word string) {
+	c.forbidden[strings.ToLower(word)] = true
+}
+
+// ForbidList adds a given list of words to forbidden words.
+func (c *Checker) ForbidList(words []string) {
+	for _, word := range words {
+		c.Forbid(word)
+	}
+}
+
+// SetSeverity sets the severity of errors of the given kind.
+func (c *Checker) SetSeverity(kind Kind, severity Severity) {
+	c.severities[kind] = severity
+}
+
+// SeverityOf returns the severity of errors of the given kind.
+func (c *Checker) SeverityOf(kind Kind) Severity {
+	if severity, ok := c.severities[kind]; ok {
+		return severity
+	}
+
+	return kind.DefaultSeverity()
+}
+
 // SetIgnoreUppercase sets Checker's ignoreUppercase flag. By default
-// a word with an uppercase letter anywhere but the start is considered
-// wrong. When ignoreUppercase is true, this behaviour is disabled.
+// words are checked using the case rules described in CheckWord. When
+// ignoreUppercase is true, a word is correct if it matches a dictionary
+// entry regardless of case.
 func (c *Checker) SetIgnoreUppercase(ignore bool) {
 	c.ignoreUppercase = ignore
 }
 
+// SetMaxLineLength sets the maximum length, in bytes, of a line in
+// checked files. Longer lines are skipped, and reported using a
+// *loader.LineLengthError. By default, and when max is 0, lines can
+// be of any length.
+func (c *Checker) SetMaxLineLength(max int) {
+	if max < 0 {
+		max = 0
+	}
+
+	c.maxLineLength = max
+}
+
+// SetDetectRepeated sets whether consecutive duplicate words, such as
+// "the the", are reported as RepeatedWord errors. Words are compared
+// case-insensitively, also across line breaks, and are only considered
+// duplicates if separated by whitespace. Disabled by default.
+func (c *Checker) SetDetectRepeated(detect bool) {
+	c.detectRepeated = detect
+}
+
+// SetCheckCapitalization sets whether words starting a sentence, and
+// proper nouns (words that only exist in the dictionary in Title-case)
+// written in lowercase are reported as Capitalization errors. Sentences
+// are separated using ".", "!", and "?", also across line breaks, and
+// a blank line always starts a new sentence. Disabled by default.
+func (c *Checker) SetCheckCapitalization(check bool) {
+	c.capitalization = check
+}
+
+// SetConfusables sets a set of confusable words, such as "their" and
+// "there", that are checked against their context, within a line, and
+// reported as ConfusedWord errors if another word of their group fits
+// the context better. A nil set, the default, disables the check.
+//
+// While confusables are set, apostrophes between letters are part of
+// words, so contractions such as "it's" are checked as one word.
+func (c *Checker) SetConfusables(set *confusion.Set) {
+	c.confusables = set
+}
+
 // CheckList checks a list of strings against a given Trie and returns
 // a slice containing incorrect words.
 func (c *Checker) CheckList(root *loader.Node, list []string) []string {
 	errors := make([]string, 0)
 	for _, word := range list {
-		if c.ignoreUppercase {
-			word = strings.ToLower(word)
-		}
-
-		if !c.ignored[word] && !CheckWord(root, word) {
+		if _, wrong := c.classify(root, word); wrong {
 			errors = append(errors, word)
 		}
 	}
@@ -95,103 +265,403 @@ func (c *Checker) CheckList(root *loader.Node, list []string) []string {
 }
 
 // CheckFile checks the file at given path for spelling errors against
-// a given Trie. Returns a list of incorrect words with their row and
-// column numbers, and an IO error if file reading fails.
+// a given Trie. Returns a list of incorrect words, sorted by their row
+// and column numbers, and an IO error if file reading fails. If lines
+// longer than the maximum are found (see SetMaxLineLength) they are
+// skipped, and a *loader.LineLengthError is returned alongside errors.
 func (c *Checker) CheckFile(root *loader.Node, path string) ([]SpellingError, error) {
+	return c.CheckFileContext(context.Background(), root, path)
+}
+
+// CheckFileContext is like CheckFile, but stops checking and returns
+// ctx's error if ctx is done before the file is checked.
+func (c *Checker) CheckFileContext(ctx context.Context, root *loader.Node, path string) ([]SpellingError, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file.Close()
 
-	errorChan := make(chan SpellingError)
-	done := make(chan bool)
+	return c.CheckReader(ctx, root, file)
+}
 
-	scanner := bufio.NewScanner(file)
-	line := 0
-	for scanner.Scan() {
-		go c.CheckLine(root, scanner.Text(), errorChan, done, line, func(c rune) bool {
-			return unicode.IsPunct(c) || (c == ' ')
-		})
+// CheckReader is like CheckFileContext, but checks text read from r.
+func (c *Checker) CheckReader(ctx context.Context, root *loader.Node, r io.Reader) ([]SpellingError, error) {
+	return collect(func(handle func(SpellingError) error) error {
+		return c.Stream(ctx, root, r, handle)
+	})
+}
 
-		line++
-	}
+// CheckReaderStats is like CheckReader, but also returns statistics of
+// the checked text.
+func (c *Checker) CheckReaderStats(ctx context.Context, root *loader.Node, r io.Reader) ([]SpellingError, *Stats, error) {
+	var stats *Stats
+	errors, err := collect(func(handle func(SpellingError) error) error {
+		var err error
+		stats, err = c.StreamStats(ctx, root, r, handle)
+		return err
+	})
+
+	return errors, stats, err
+}
 
-	// If an IO error occurred
-	if err := scanner.Err(); err != nil {
+// collect calls stream with a function collecting errors, and returns
+// them sorted by their row and column numbers.
+func collect(stream func(handle func(SpellingError) error) error) ([]SpellingError, error) {
+	errors := make([]SpellingError, 0)
+	err := stream(func(e SpellingError) error {
+		errors = append(errors, e)
+		return nil
+	})
+
+	if _, ok := err.(*loader.LineLengthError); err != nil && !ok {
 		return nil, err
 	}
 
+	sort.Slice(errors, func(i, j int) bool {
+		if errors[i].Row != errors[j].Row {
+			return errors[i].Row < errors[j].Row
+		}
+
+		return errors[i].Col < errors[j].Col
+	})
+
+	return errors, err
+}
+
+// StreamFile checks the file at given path for spelling errors against
+// a given Trie, and calls handle for each error found. See Stream.
+func (c *Checker) StreamFile(ctx context.Context, root *loader.Node, path string, handle func(SpellingError) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.Stream(ctx, root, file, handle)
+}
+
+// line is a line of text waiting to be checked.
+type line struct {
+	row   int
+	text  string
+	prev  string // Last word of the previous line, see lineEnd
+	start bool   // True if the line starts a sentence
+}
+
+// checked is a checked line.
+type checked struct {
+	words  []word // Words checked against the dictionary, counted in Stats
+	errors []SpellingError
+}
+
+// Stream reads text from r line by line, and checks the lines against
+// a given Trie using a bounded pool of workers (see SetWorkers.) handle
+// is called, from a single goroutine, for each spelling error as soon
+// as it is found, so errors are not necessarily ordered.
+//
+// Checking stops when ctx is done, or when handle returns an error, in
+// which case that error is returned. Otherwise an IO error is returned
+// if reading fails, or a *loader.LineLengthError after checking is done
+// if lines longer than the maximum were found and skipped.
+func (c *Checker) Stream(ctx context.Context, root *loader.Node, r io.Reader, handle func(SpellingError) error) error {
+	return c.stream(ctx, root, r, handle, nil)
+}
+
+// StreamStats is like Stream, but also returns statistics of the checked
+// text, which are partial if checking stops early.
+func (c *Checker) StreamStats(ctx context.Context, root *loader.Node, r io.Reader, handle func(SpellingError) error) (*Stats, error) {
+	stats := NewStats()
+	start := time.Now()
+	err := c.stream(ctx, root, r, handle, stats)
+	stats.Elapsed = time.Since(start)
+
+	return stats, err
+}
+
+// stream implements Stream, counting checked words and found errors in
+// stats unless it's nil.
+func (c *Checker) stream(ctx context.Context, root *loader.Node, r io.Reader, handle func(SpellingError) error, stats *Stats) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan line, c.workers)
+	results := make(chan checked, c.workers)
+	readErr := make(chan error, 1)
+
+	// Read lines
 	go func() {
-		for count := 0; count < line; count++ {
-			<-done
+		defer close(pending)
+
+		var long []int
+		var prev string
+		start := true
+		reader := lines.NewReader(r, c.maxLineLength)
+		for row := 0; ; row++ {
+			text, isLong, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			if isLong {
+				long = append(long, row)
+				prev, start = "", false
+				continue
+			}
+
+			l := line{row, text, prev, start}
+			prev, start = lineEnd(text, wordEnd, c.confusables != nil)
+
+			select {
+			case pending <- l:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
 		}
 
-		close(errorChan)
+		if len(long) != 0 {
+			readErr <- &loader.LineLengthError{Max: c.maxLineLength, Rows: long}
+			return
+		}
+
+		readErr <- nil
 	}()
 
-	errors := make([]SpellingError, 0)
-	for word := range errorChan {
-		errors = append(errors, word)
+	// Check lines
+	var wg sync.WaitGroup
+	wg.Add(c.workers)
+	for i := 0; i < c.workers; i++ {
+		go func() {
+			defer wg.Done()
+			for l := range pending {
+				if ctx.Err() != nil {
+					return
+				}
+
+				words := split(l.text, wordEnd, c.confusables != nil)
+				errors := c.wordErrors(root, l, words)
+				if len(errors) == 0 && stats == nil {
+					continue
+				}
+
+				if stats != nil {
+					words = c.checkedWords(words)
+				}
+
+				select {
+				case results <- checked{words, errors}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
 	}
 
-	return errors, nil
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if stats != nil {
+			stats.addWords(result.words)
+		}
+
+		for _, e := range result.errors {
+			if stats != nil {
+				stats.addError(e)
+			}
+
+			if err := handle(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := <-readErr; err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// checkedWords returns words checked against the dictionary, that is
+// words that aren't ignored, unless they're forbidden.
+func (c *Checker) checkedWords(words []word) []word {
+	var checked []word
+	for _, w := range words {
+		if !c.isIgnored(w.text) || c.forbidden[strings.ToLower(w.text)] {
+			checked = append(checked, w)
+		}
+	}
+
+	return checked
+}
+
+// wordEnd is the default word separator used when checking files.
+func wordEnd(c rune) bool {
+	return unicode.IsPunct(c) || (c == ' ')
 }
 
 // CheckLine takes a line of text (string containing multiple words), seperates the
 // line into words using wordEnd function, checks each word in the line against
 // the given trie, and pushes incorrect words to errorChan. After line evaluation is
-// finished, true is sent as a singal to done channel.
-func (c *Checker) CheckLine(root *loader.Node, line string, errorChan chan SpellingError, done chan bool, lineNumber int, wordEnd func(c rune) bool) {
-	words := strings.FieldsFunc(line, wordEnd)
-	for i, word := range words {
-		if c.ignoreUppercase {
-			word = strings.ToLower(word)
-		}
-
-		if !c.ignored[word] && !CheckWord(root, word) {
-			errorChan <- SpellingError{word, lineNumber, i}
-		}
+// finished, true is sent as a singal to done channel. The line is checked as
+// the start of a sentence.
+func (c *Checker) CheckLine(root *loader.Node, text string, errorChan chan SpellingError, done chan bool, lineNumber int, wordEnd func(c rune) bool) {
+	for _, err := range c.lineErrors(root, line{row: lineNumber, text: text, start: true}, wordEnd) {
+		errorChan <- err
 	}
 
 	done <- true
 }
 
-// CheckWord verifies a given word against a given Trie, returns
-// true if word exists in the given trie, false otherwise.
-func CheckWord(root *loader.Node, word string) bool {
-	return recCheck(root, word, 0)
+// lineErrors separates a line into words using wordEnd function, and
+// returns a list of errors in the line.
+func (c *Checker) lineErrors(root *loader.Node, l line, wordEnd func(c rune) bool) []SpellingError {
+	return c.wordErrors(root, l, split(l.text, wordEnd, c.confusables != nil))
 }
 
-// recCheck, recursively, verifies that a word exists in the given trie.
-func recCheck(root *loader.Node, word string, charNumber int) bool {
-	if charNumber == len(word) {
-		return root.IsWord()
+// wordErrors returns a list of errors in a line, given its words.
+func (c *Checker) wordErrors(root *loader.Node, l line, words []word) []SpellingError {
+	var errors []SpellingError
+
+	// Confusables are checked in lowercase
+	var lower []string
+	if c.confusables != nil {
+		lower = make([]string, len(words))
+		for i, w := range words {
+			lower[i] = strings.ToLower(w.text)
+		}
 	}
 
-	// Check if character is ASCII
-	if word[charNumber] >= 32 && word[charNumber] <= unicode.MaxASCII {
+	for i, w := range words {
+		word := w.text
+		kind, wrong := c.classify(root, word)
+		if wrong {
+			errors = append(errors, SpellingError{Word: word, Row: l.row, Col: i, Offset: w.start, Kind: kind})
+		}
 
-		// Uppercase character
-		if word[charNumber] >= 65 && word[charNumber] <= 90 {
-			if charNumber == 0 {
-				// Pass character as uppercase
-				if root.Children()[word[charNumber]] != nil {
-					return recCheck(root.Children()[word[charNumber]], word, charNumber+1)
-				}
+		if c.capitalization && !wrong && !c.isIgnored(word) {
+			if err, ok := capitalization(root, l, words, i); ok {
+				errors = append(errors, err)
 			}
+		}
 
-			return false
+		if c.confusables != nil && !wrong && !c.isIgnored(word) {
+			if suggestion, ok := c.confusables.Check(lower, i); ok {
+				errors = append(errors, confused(l, w, i, suggestion))
+			}
 		}
 
-		// Check if character exists
-		if root.Children()[word[charNumber]-loader.FirstPrintableASCII] != nil {
-			return recCheck(root.Children()[word[charNumber]-loader.FirstPrintableASCII], word, charNumber+1)
+		if c.detectRepeated {
+			if err, ok := repeated(l, words, i); ok {
+				errors = append(errors, err)
+			}
 		}
+	}
 
-		return false
+	for i := range errors {
+		errors[i].Severity = c.SeverityOf(errors[i].Kind)
+	}
 
+	return errors
+}
+
+// classify checks a word against a given Trie, and the Checker's
+// ignored and forbidden words. Returns the kind of the word's error,
+// and true if the word is incorrect.
+func (c *Checker) classify(root *loader.Node, word string) (Kind, bool) {
+	if c.forbidden[strings.ToLower(word)] {
+		return ForbiddenWord, true
 	}
 
-	return false
+	if c.isIgnored(word) {
+		return UnknownWord, false
+	}
+
+	correct, known := checkCase(root, word, c.ignoreUppercase)
+	if !correct && !known && c.compound != nil {
+		correct = CheckCompound(root, word, *c.compound)
+	}
+
+	switch {
+	case correct:
+		return UnknownWord, false
+	case known:
+		return BadCasing, true
+	default:
+		return UnknownWord, true
+	}
+}
+
+// word is a word in a line of text.
+type word struct {
+	text  string
+	start int // Byte offset of the word in its line
+}
+
+// end returns the byte offset after the word's last byte.
+func (w word) end() int {
+	return w.start + len(w.text)
+}
+
+// split separates a line into words using wordEnd function. It's
+// equivalent to strings.FieldsFunc, but keeps words' offsets, and if
+// apostrophes is set, apostrophes between letters, as in "it's". These
+// are only kept when checking confusables, which include contractions.
+func split(text string, wordEnd func(c rune) bool, apostrophes bool) []word {
+	var words []word
+
+	start := -1
+	for i, r := range text {
+		if separates(text, i, r, wordEnd, apostrophes) {
+			if start >= 0 {
+				words = append(words, word{text[start:i], start})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, word{text[start:], start})
+	}
+
+	return words
+}
+
+// separates returns true if rune r at byte offset i of text separates
+// words. If apostrophes is set, an apostrophe is part of a word if it's
+// between two letters.
+func separates(text string, i int, r rune, wordEnd func(c rune) bool, apostrophes bool) bool {
+	if r != '\'' || !apostrophes {
+		return wordEnd(r)
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i+1:])
+	return !unicode.IsLetter(before) || !unicode.IsLetter(after)
+}
+
+// CheckWord verifies a given word against a given Trie, returns
+// true if word exists in the given trie, false otherwise.
+//
+// Words are matched against dictionary entries using the following
+// case rules
+//   - A lowercase entry ("hello") matches the word as is, its Title-case
+//     ("Hello"), and ALL-CAPS ("HELLO") variants.
+//   - A Title-case entry ("Paris") matches the word as is, its ALL-CAPS
+//     ("PARIS"), and lowercase ("paris") variants.
+//   - Any other entry ("iOS", "NASA") must be matched exactly.
+func CheckWord(root *loader.Node, word string) bool {
+	correct, _ := checkCase(root, word, false)
+	return correct
 }
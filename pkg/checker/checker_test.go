@@ -1,10 +1,17 @@
 package checker
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/sudo-sturbia/gocheck/v3/pkg/confusion"
 	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/phonetic"
 )
 
 // Example is a usage example for checker package.
@@ -203,13 +210,13 @@ func TestCheckListWithOptions(t *testing.T) {
 		"long",
 	}
 
+	// "ITS" is an ALL-CAPS variant of "its", and is correct
 	shouldFind := []string{
 		"oF",
 		"aNd",
 		"tHINk",
 		"hoW",
 		"diFfeRent",
-		"ITS",
 		"cOurse",
 	}
 
@@ -244,6 +251,83 @@ func TestCheckListWithOptions(t *testing.T) {
 	if found[0] != "tHINk" {
 		t.Errorf("Didn't find %s.", "tHINk")
 	}
+
+	// Retry with an ignored pattern
+	c.IgnorePattern(regexp.MustCompile(`^t[A-Z]+k$`))
+	if found = c.CheckList(root, words); len(found) != 0 {
+		t.Errorf("Expected 0 errors, found %d.", len(found))
+	}
+
+	// Patterns are cleared with ignored words
+	c.ClearIgnored(true)
+	if found = c.CheckList(root, words); len(found) != len(shouldFind) {
+		t.Errorf("Expected %d errors, found %d.", len(shouldFind), len(found))
+	}
+}
+
+// Test checking text read from a reader.
+func TestCheckReader(t *testing.T) {
+	c := New()
+	found, err := c.CheckReader(context.Background(), root, strings.NewReader("sme day\nit wsa\n"))
+	if err != nil {
+		t.Fatalf("Checking failed: %v.", err)
+	}
+
+	expected := []SpellingError{
+		{Word: "sme", Row: 0, Col: 0},
+		{Word: "wsa", Row: 1, Col: 1},
+	}
+
+	if len(found) != len(expected) {
+		t.Fatalf("Expected %d errors, found %d.", len(expected), len(found))
+	}
+
+	for i, e := range expected {
+		if found[i].Word != e.Word || found[i].Row != e.Row || found[i].Col != e.Col {
+			t.Errorf("Expected %+v, found %+v.", e, found[i])
+		}
+	}
+}
+
+// Test statistics of checked text.
+func TestCheckReaderStats(t *testing.T) {
+	c := New()
+	found, stats, err := c.CheckReaderStats(context.Background(), root, strings.NewReader("sme day\nsme day wsa\n"))
+	if err != nil {
+		t.Fatalf("Checking failed: %v.", err)
+	}
+
+	if len(found) != 3 || stats.Words != 5 || stats.Unique() != 3 || stats.Errors != 3 {
+		t.Errorf("Expected 3 errors in 5 words, 3 unique, found %d errors, stats %+v.", len(found), stats)
+	}
+
+	if rate := stats.Rate(); rate != 0.6 {
+		t.Errorf("Expected an error rate of 0.6, found %v.", rate)
+	}
+
+	expected := []Misspelling{{"sme", 2}, {"wsa", 1}}
+	if misspellings := stats.Misspellings(0); !reflect.DeepEqual(misspellings, expected) {
+		t.Errorf("Expected misspellings %v, found %v.", expected, misspellings)
+	}
+
+	total := NewStats()
+	total.Add(stats)
+	total.Add(stats)
+	if total.Words != 10 || total.Unique() != 3 || total.Misspellings(1)[0].Count != 4 {
+		t.Errorf("Expected 10 words, 3 unique, and 4 occurrences of \"sme\", found %+v.", total)
+	}
+
+	total.SetErrors(found[:1])
+	if total.Errors != 1 || len(total.Misspellings(0)) != 1 {
+		t.Errorf("Expected 1 error after setting errors, found %d.", total.Errors)
+	}
+
+	// Ignored words aren't counted
+	c.Ignore("day")
+	_, stats, err = c.CheckReaderStats(context.Background(), root, strings.NewReader("sme day\nsme day wsa\n"))
+	if err != nil || stats.Words != 3 || stats.Unique() != 2 {
+		t.Errorf("Expected 3 words, 2 unique, found %+v (%v).", stats, err)
+	}
 }
 
 // Test file checking on a file without errors.
@@ -273,14 +357,14 @@ func TestCheckFileWithErrors(t *testing.T) {
 
 	// Assert errors found in file
 	shouldFind := []SpellingError{
-		SpellingError{"memmorable", 0, 3},
-		SpellingError{"mde", 0, 9},
-		SpellingError{"s12eleted", 1, 2},
-		SpellingError{"stu", 1, 4},
-		SpellingError{"ck", 1, 5},
-		SpellingError{"th", 2, 11},
-		SpellingError{"nevsdfser", 3, 2},
-		SpellingError{"rmation", 3, 9},
+		SpellingError{Word: "memmorable", Row: 0, Col: 3},
+		SpellingError{Word: "mde", Row: 0, Col: 9},
+		SpellingError{Word: "s12eleted", Row: 1, Col: 2},
+		SpellingError{Word: "stu", Row: 1, Col: 4},
+		SpellingError{Word: "ck", Row: 1, Col: 5},
+		SpellingError{Word: "th", Row: 2, Col: 11},
+		SpellingError{Word: "nevsdfser", Row: 3, Col: 2},
+		SpellingError{Word: "rmation", Row: 3, Col: 9},
 	}
 
 	// Push found errors to a map
@@ -291,12 +375,556 @@ func TestCheckFileWithErrors(t *testing.T) {
 
 	// Compare found with shouldFind
 	for _, err := range shouldFind {
-		if foundMap[err.Word] != err {
+		if e, ok := foundMap[err.Word]; !ok || e.Row != err.Row || e.Col != err.Col {
 			t.Errorf("Didn't find %v.", err)
 		}
 	}
 }
 
+// Test that checking a file with one worker finds the same errors.
+func TestCheckFileOneWorker(t *testing.T) {
+	c := New()
+	c.SetWorkers(1)
+	found, err := c.CheckFile(root, "../../test-data/wrong-paragraph.txt")
+	if err != nil {
+		t.Errorf("File reading failed.")
+	}
+
+	if len(found) != 8 {
+		t.Errorf("Incorrect number of spelling errors. Expected 8, Found %d.", len(found))
+	}
+
+	// Errors should be sorted by position
+	for i := 1; i < len(found); i++ {
+		if found[i-1].Row > found[i].Row ||
+			(found[i-1].Row == found[i].Row && found[i-1].Col > found[i].Col) {
+			t.Errorf("Errors are not sorted: %v before %v.", found[i-1], found[i])
+		}
+	}
+}
+
+// Test that CheckFile returns an error for a missing file.
+func TestCheckFileMissing(t *testing.T) {
+	c := New()
+	if _, err := c.CheckFile(root, "../../test-data/does-not-exist.txt"); err == nil {
+		t.Errorf("Expected an error for a missing file.")
+	}
+}
+
+// Test streaming errors from a reader.
+func TestStream(t *testing.T) {
+	c := New()
+	text := "that was a dy\nto me fr it\n"
+
+	found := make(map[string]bool)
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		found[e.Word] = true
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Stream failed: %v.", err)
+	}
+
+	if len(found) != 2 || !found["dy"] || !found["fr"] {
+		t.Errorf("Expected \"dy\" and \"fr\", found %v.", found)
+	}
+}
+
+// Test that an error returned by the handler stops streaming.
+func TestStreamHandlerError(t *testing.T) {
+	c := New()
+	text := strings.Repeat("tht ws a dy\n", 1000)
+	stop := errors.New("stop")
+
+	calls := 0
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		calls++
+		return stop
+	})
+
+	if err != stop {
+		t.Errorf("Expected handler's error, found %v.", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Handler was called %d times after returning an error.", calls)
+	}
+}
+
+// Test that a cancelled context stops checking.
+func TestCheckFileContextCancelled(t *testing.T) {
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.CheckFileContext(ctx, root, "../../test-data/wrong-paragraph.txt"); err != context.Canceled {
+		t.Errorf("Expected %v, found %v.", context.Canceled, err)
+	}
+}
+
+// Test checking text containing lines longer than bufio's default
+// buffer size.
+func TestStreamLongLine(t *testing.T) {
+	c := New()
+	text := "that was\n" + strings.Repeat("a dy ", 50000) + "\nto me\n"
+
+	count := 0
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		if e.Word != "dy" || e.Row != 1 {
+			t.Errorf("Unexpected error %v.", e)
+		}
+
+		count++
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Stream failed: %v.", err)
+	}
+
+	if count != 50000 {
+		t.Errorf("Expected 50000 errors, found %d.", count)
+	}
+}
+
+// Test that lines longer than a maximum are skipped and reported.
+func TestStreamMaxLineLength(t *testing.T) {
+	c := New()
+	c.SetMaxLineLength(20)
+	text := "that ws\n" + strings.Repeat("a dy ", 100) + "\nto mee\n"
+
+	found := make(map[string]bool)
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		found[e.Word] = true
+		return nil
+	})
+
+	var lengthErr *loader.LineLengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("Expected a LineLengthError, found %v.", err)
+	}
+
+	if len(lengthErr.Rows) != 1 || lengthErr.Rows[0] != 1 {
+		t.Errorf("Expected long line at row 1, found %v.", lengthErr.Rows)
+	}
+
+	if len(found) != 2 || !found["ws"] || !found["mee"] {
+		t.Errorf("Expected \"ws\" and \"mee\", found %v.", found)
+	}
+}
+
+// Test detection of repeated words.
+func TestRepeatedWords(t *testing.T) {
+	c := New()
+	c.SetDetectRepeated(true)
+	text := "that was the The day\nto me, me. for\nit made it\nit   \n  It was a day\nday\n"
+
+	var found []SpellingError
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		found = append(found, e)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Stream failed: %v.", err)
+	}
+
+	// Map rows to errors
+	rows := make(map[int]SpellingError)
+	for _, e := range found {
+		if e.Kind != RepeatedWord {
+			t.Errorf("Unexpected error %v.", e)
+		}
+
+		rows[e.Row] = e
+	}
+
+	if len(found) != 4 {
+		t.Fatalf("Expected 4 repeated words, found %d: %v.", len(found), found)
+	}
+
+	lines := strings.Split(text, "\n")
+	shouldFind := []struct {
+		row   int
+		word  string
+		fixed string
+	}{
+		{0, "The", "that was the day"},
+		{3, "it", ""},
+		{4, "It", "  was a day"},
+		{5, "day", ""},
+	}
+
+	for _, s := range shouldFind {
+		e, ok := rows[s.row]
+		if !ok || e.Word != s.word {
+			t.Errorf("Didn't find %q at row %d.", s.word, s.row)
+			continue
+		}
+
+		if e.Fix == nil || e.Fix.Row != s.row {
+			t.Errorf("Expected a fix for %v.", e)
+			continue
+		}
+
+		if fixed := e.Fix.Apply(lines[s.row]); fixed != s.fixed {
+			t.Errorf("Expected fixed line %q, found %q.", s.fixed, fixed)
+		}
+
+		// Removing a word isn't a correction of it
+		if correction, ok := e.Correction(); ok {
+			t.Errorf("Unexpected correction %q for %v.", correction, e)
+		}
+	}
+}
+
+// Test detection of capitalization errors.
+func TestCapitalization(t *testing.T) {
+	dictionary := loader.LoadList([]string{
+		"paris", "Paris", "is", "in", "France", "it", "was",
+		"a", "day", "Mr", "Smith", "iOS", "runs", "on", "the", "phone",
+	})
+
+	c := New()
+	c.SetCheckCapitalization(true)
+	text := "paris is in france. it was a day\n" +
+		"Mr. Smith was in Paris! iOS runs on\n" +
+		"the phone. On a day\n" +
+		"\n" +
+		"it was 3.5 in Paris.\n" +
+		"the day\n"
+
+	found := make(map[[2]int]SpellingError)
+	err := c.Stream(context.Background(), dictionary, strings.NewReader(text), func(e SpellingError) error {
+		if e.Kind == Capitalization {
+			found[[2]int{e.Row, e.Col}] = e
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Stream failed: %v.", err)
+	}
+
+	shouldFind := []SpellingError{
+		SpellingError{Word: "paris", Row: 0, Col: 0},
+		SpellingError{Word: "france", Row: 0, Col: 3},
+		SpellingError{Word: "it", Row: 0, Col: 4},
+		SpellingError{Word: "it", Row: 4, Col: 0},
+		SpellingError{Word: "the", Row: 5, Col: 0},
+	}
+
+	if len(found) != len(shouldFind) {
+		t.Errorf("Expected %d errors, found %d: %v.", len(shouldFind), len(found), found)
+	}
+
+	lines := strings.Split(text, "\n")
+	for _, err := range shouldFind {
+		e, ok := found[[2]int{err.Row, err.Col}]
+		if !ok || e.Word != err.Word {
+			t.Errorf("Didn't find %v.", err)
+			continue
+		}
+
+		fixed := e.Fix.Apply(lines[e.Row])
+		if fixed[e.Offset:e.Offset+1] != strings.ToUpper(e.Word[:1]) {
+			t.Errorf("Fix didn't capitalize %q: %q.", e.Word, fixed)
+		}
+	}
+
+	// A line checked alone starts a sentence
+	errorChan, done := make(chan SpellingError, 10), make(chan bool, 1)
+	c.CheckLine(dictionary, "it was a day", errorChan, done, 2, wordEnd)
+	<-done
+	close(errorChan)
+
+	var checked []SpellingError
+	for e := range errorChan {
+		checked = append(checked, e)
+	}
+
+	if len(checked) != 1 || checked[0].Kind != Capitalization || checked[0].Word != "it" || checked[0].Row != 2 {
+		t.Errorf("Expected \"it\" at row 2 to be capitalized, found %v.", checked)
+	}
+
+	// A correct paragraph has no capitalization errors
+	errors, err := c.CheckFile(root, "../../test-data/paragraph.txt")
+	if err != nil || len(errors) != 0 {
+		t.Errorf("Found errors in a correct file: %v, %v.", errors, err)
+	}
+}
+
+// Test detection of confused words.
+func TestConfusedWords(t *testing.T) {
+	set, err := confusion.LoadFile("../../data/confusables.txt")
+	if err != nil {
+		t.Fatalf("Loading confusables failed: %v.", err)
+	}
+
+	dictionary := loader.LoadList([]string{
+		"their", "there", "they're", "its", "it's", "own", "is", "a", "day", "dog", "wagged", "tail",
+	})
+
+	c := New()
+	c.SetConfusables(set)
+	text := "There own day\nits a day\nthe dog wagged it's tail\nthere is a day\n"
+
+	var found []SpellingError
+	err = c.Stream(context.Background(), dictionary, strings.NewReader(text), func(e SpellingError) error {
+		if e.Kind == ConfusedWord {
+			found = append(found, e)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Stream failed: %v.", err)
+	}
+
+	shouldFind := map[string]string{
+		"There": "Their own day",
+		"its":   "it's a day",
+	}
+
+	if len(found) != len(shouldFind) {
+		t.Errorf("Expected %d errors, found %d: %v.", len(shouldFind), len(found), found)
+	}
+
+	lines := strings.Split(text, "\n")
+	for _, e := range found {
+		fixed, ok := shouldFind[e.Word]
+		if !ok {
+			t.Errorf("Unexpected error %v.", e)
+			continue
+		}
+
+		if e.Fix == nil || e.Fix.Apply(lines[e.Row]) != fixed {
+			t.Errorf("Expected %q to be fixed as %q.", e.Word, fixed)
+		}
+
+		if correction, ok := e.Correction(); !ok || !strings.HasPrefix(fixed, correction+" ") {
+			t.Errorf("Unexpected correction %q for %q.", correction, e.Word)
+		}
+	}
+}
+
+// Test splitting lines into words, with and without keeping apostrophes.
+func TestSplit(t *testing.T) {
+	text := "it's 'quoted' they're, o'clock'"
+	for _, test := range []struct {
+		apostrophes bool
+		expected    []string
+	}{
+		{true, []string{"it's", "quoted", "they're", "o'clock"}},
+		{false, []string{"it", "s", "quoted", "they", "re", "o", "clock"}},
+	} {
+		words := split(text, wordEnd, test.apostrophes)
+		if len(words) != len(test.expected) {
+			t.Fatalf("Expected %q, found %v.", test.expected, words)
+		}
+
+		for i, w := range words {
+			if w.text != test.expected[i] {
+				t.Errorf("Expected %q, found %q.", test.expected[i], w.text)
+			}
+		}
+	}
+}
+
+// Test suggestions for misspelled words.
+func TestSuggest(t *testing.T) {
+	c := New()
+
+	tests := map[string]string{
+		"formaton":   "formation",
+		"memmorable": "memorable",
+		"chian":      "chain",
+		"Thnik":      "Think",
+		"GOLDD":      "GOLD",
+	}
+
+	for word, expected := range tests {
+		suggestions := c.Suggest(root, word)
+		if len(suggestions) == 0 || suggestions[0] != expected {
+			t.Errorf("Expected %q to be suggested first for %q, found %v.", expected, word, suggestions)
+		}
+	}
+
+	c.SetMaxSuggestions(2)
+	if suggestions := c.Suggest(root, "th"); len(suggestions) > 2 {
+		t.Errorf("Expected at most 2 suggestions, found %v.", suggestions)
+	}
+
+	if suggestions := c.Suggest(root, "xqzvw"); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, found %v.", suggestions)
+	}
+}
+
+// Test ranking suggestions using frequencies.
+func TestSuggestFrequency(t *testing.T) {
+	dictionary, err := loader.LoadReader(strings.NewReader("the\t50000\nthee\t12\nten\t900\ntea\t300\n"), loader.Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	c := New()
+	suggestions := c.Suggest(dictionary, "teh")
+
+	// "thee" is 2 edits away, too far for a short word
+	expected := []string{"the", "ten", "tea"}
+	if strings.Join(suggestions, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v, found %v.", expected, suggestions)
+	}
+}
+
+// Test suggesting sound-alike words.
+func TestSuggestPhonetic(t *testing.T) {
+	dictionary, err := loader.LoadReader(strings.NewReader(
+		"phonetic\t900\nfanatic\t200\nfrantic\t300\nkinetic\t100\ngenetic\t400\nnight\t800\nnine\t900\n",
+	), loader.Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	c := New()
+	if suggestions := c.Suggest(dictionary, "fonetik"); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions without a phonetic index, found %v.", suggestions)
+	}
+
+	c.SetPhoneticIndex(phonetic.NewIndex(dictionary))
+	// "fanatic" sounds alike too, but is less frequent
+	suggestions := c.Suggest(dictionary, "fonetik")
+	if len(suggestions) != 2 || suggestions[0] != "phonetic" || suggestions[1] != "fanatic" {
+		t.Errorf("Expected \"phonetic\" and \"fanatic\", found %v.", suggestions)
+	}
+
+	// "night" is too far from "nite" to be suggested, unless it's a
+	// sound-alike word
+	suggestions = c.Suggest(dictionary, "nite")
+	if len(suggestions) != 2 || suggestions[1] != "night" {
+		t.Errorf("Expected \"night\" to be suggested, found %v.", suggestions)
+	}
+}
+
+// Test weighting suggestions using a keyboard layout.
+func TestSuggestKeyboard(t *testing.T) {
+	dictionary := loader.LoadList([]string{"word", "ward", "the", "tea", "ten"})
+
+	tests := []struct {
+		layout   *Layout
+		word     string
+		expected string
+	}{
+		{nil, "wprd", "ward"},
+		{QWERTY, "wprd", "word"},
+		{nil, "teh", "tea"},
+		{QWERTY, "teh", "the"},
+		{AZERTY, "teh", "the"},
+		{AZERTY, "wprd", "word"},
+		{Dvorak, "wprd", "ward"},
+		{QWERTY, "werd", "ward"},
+		{Dvorak, "werd", "word"},
+	}
+
+	c := New()
+	for _, test := range tests {
+		c.SetKeyboard(test.layout)
+		suggestions := c.Suggest(dictionary, test.word)
+		if len(suggestions) == 0 || suggestions[0] != test.expected {
+			t.Errorf("Expected %q to be suggested first for %q using %v, found %v.",
+				test.expected, test.word, test.layout, suggestions)
+		}
+	}
+}
+
+// Test keyboard layouts.
+func TestLayout(t *testing.T) {
+	for _, layout := range Layouts {
+		if parsed, err := ParseLayout(layout.String()); err != nil || parsed != layout {
+			t.Errorf("Failed to parse %s, found %v, %v.", layout, parsed, err)
+		}
+	}
+
+	if _, err := ParseLayout("colemak"); err == nil {
+		t.Errorf("Parsed an unknown layout.")
+	}
+
+	adjacent := [][2]byte{{'q', 'w'}, {'s', 'w'}, {'s', 'e'}, {'z', 'a'}, {'g', 'b'}}
+	for _, pair := range adjacent {
+		if !QWERTY.Adjacent(pair[0], pair[1]) || !QWERTY.Adjacent(pair[1], pair[0]) {
+			t.Errorf("Expected %c and %c to be adjacent.", pair[0], pair[1])
+		}
+	}
+
+	distant := [][2]byte{{'q', 'e'}, {'s', 'q'}, {'z', 'd'}, {'a', 'a'}, {'a', '1'}}
+	for _, pair := range distant {
+		if QWERTY.Adjacent(pair[0], pair[1]) {
+			t.Errorf("Expected %c and %c not to be adjacent.", pair[0], pair[1])
+		}
+	}
+}
+
+// Test kinds and severities of found errors.
+func TestErrorKinds(t *testing.T) {
+	c := New()
+	c.Forbid("Gold")
+	c.SetSeverity(BadCasing, Info)
+	text := "that was a dAy of gold and irn\n"
+
+	found := make(map[string]SpellingError)
+	err := c.Stream(context.Background(), root, strings.NewReader(text), func(e SpellingError) error {
+		found[e.Word] = e
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Stream failed: %v.", err)
+	}
+
+	shouldFind := []SpellingError{
+		SpellingError{Word: "dAy", Kind: BadCasing, Severity: Info},
+		SpellingError{Word: "gold", Kind: ForbiddenWord, Severity: Error},
+		SpellingError{Word: "irn", Kind: UnknownWord, Severity: Error},
+	}
+
+	if len(found) != len(shouldFind) {
+		t.Errorf("Expected %d errors, found %d.", len(shouldFind), len(found))
+	}
+
+	for _, err := range shouldFind {
+		e, ok := found[err.Word]
+		if !ok || e.Kind != err.Kind || e.Severity != err.Severity {
+			t.Errorf("Expected %q to be a %s (%s), found %v.", err.Word, err.Kind, err.Severity, e)
+		}
+	}
+}
+
+// Test parsing kinds and severities from their names.
+func TestParseKindAndSeverity(t *testing.T) {
+	for _, kind := range Kinds {
+		if parsed, err := ParseKind(kind.String()); err != nil || parsed != kind {
+			t.Errorf("Failed to parse %s, found %s, %v.", kind, parsed, err)
+		}
+	}
+
+	for _, severity := range []Severity{Info, Warning, Error} {
+		if parsed, err := ParseSeverity(severity.String()); err != nil || parsed != severity {
+			t.Errorf("Failed to parse %s, found %s, %v.", severity, parsed, err)
+		}
+	}
+
+	if _, err := ParseKind("typo"); err == nil {
+		t.Errorf("Parsed an unknown kind.")
+	}
+
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Errorf("Parsed an unknown severity.")
+	}
+}
+
 // Test word checking using correct words.
 func TestCheckWordExists(t *testing.T) {
 	words := []string{
@@ -332,6 +960,129 @@ func TestCheckWordExists(t *testing.T) {
 	}
 }
 
+// Test checking of compound words.
+func TestCheckCompound(t *testing.T) {
+	dictionary := loader.LoadList([]string{
+		"Arbeit", "Zeit", "Haus", "tuer", "fiets", "pad", "iOS", "ab",
+	})
+
+	opts := CompoundOptions{Linking: []string{"s"}}
+	correct := []string{
+		"Arbeitszeit", "arbeitszeit", "ARBEITSZEIT",
+		"Haustuer", "Hausarbeitszeit", "fietspad",
+		"Zeit",
+	}
+
+	for _, word := range correct {
+		if !CheckCompound(dictionary, word, opts) {
+			t.Errorf("\"%s\" should be correct, but isn't.", word)
+		}
+	}
+
+	incorrect := []string{
+		"Arbeitzeitx", "HausTuer", "iosfiets", "abhaus", "Arbeits", "Arbeitenzeit",
+	}
+
+	for _, word := range incorrect {
+		if CheckCompound(dictionary, word, opts) {
+			t.Errorf("\"%s\" shouldn't be correct, but is.", word)
+		}
+	}
+
+	// Linking morphemes must be allowed
+	if CheckCompound(dictionary, "Arbeitszeit", CompoundOptions{}) {
+		t.Errorf("\"Arbeitszeit\" shouldn't be correct without linking morphemes.")
+	}
+
+	// Minimum part length
+	if !CheckCompound(dictionary, "abhaus", CompoundOptions{MinPartLength: 2}) {
+		t.Errorf("\"abhaus\" should be correct with a minimum part length of 2.")
+	}
+
+	// Flags
+	flagged, err := loader.LoadReader(strings.NewReader("haus\tB\ntuer\tE\nzeit\nnasa\tN\nfiets\tC\n"), loader.Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	for word, expected := range map[string]bool{
+		"haustuer":  true,
+		"tuerhaus":  false,
+		"hauszeit":  false,
+		"hausfiets": true,
+		"nasatuer":  false,
+	} {
+		if found := CheckCompound(flagged, word, CompoundOptions{RequireFlags: true}); found != expected {
+			t.Errorf("Expected \"%s\" to be %t, found %t.", word, expected, found)
+		}
+	}
+
+	if !CheckCompound(flagged, "hauszeit", CompoundOptions{}) {
+		t.Errorf("\"hauszeit\" should be correct if flags aren't required.")
+	}
+
+	if CheckCompound(flagged, "nasazeit", CompoundOptions{}) {
+		t.Errorf("\"nasazeit\" shouldn't be correct, \"nasa\" may not compound.")
+	}
+
+	// Checker
+	c := New()
+	c.SetCompound(&opts)
+	if errs := c.CheckList(dictionary, []string{"Arbeitszeit", "Arbeitzeitx"}); len(errs) != 1 || errs[0] != "Arbeitzeitx" {
+		t.Errorf("Expected [Arbeitzeitx], found %v.", errs)
+	}
+}
+
+// Test case rules of word checking.
+func TestCheckWordCase(t *testing.T) {
+	dictionary := loader.LoadList([]string{
+		"hello",
+		"Paris",
+		"iOS",
+		"NASA",
+	})
+
+	correct := []string{
+		"hello", "Hello", "HELLO",
+		"Paris", "PARIS", "paris",
+		"iOS",
+		"NASA",
+	}
+
+	for _, word := range correct {
+		if !CheckWord(dictionary, word) {
+			t.Errorf("\"%s\" should be correct, but isn't.", word)
+		}
+	}
+
+	incorrect := []string{
+		"hELLO", "HeLLo",
+		"pARIS", "PaRis",
+		"IOS", "ios", "Ios",
+		"nasa", "Nasa",
+	}
+
+	for _, word := range incorrect {
+		if CheckWord(dictionary, word) {
+			t.Errorf("\"%s\" shouldn't be correct, but is.", word)
+		}
+	}
+
+	// Incorrectly cased words are known, and reported as BadCasing
+	c := New()
+	for _, word := range incorrect {
+		if kind, wrong := c.classify(dictionary, word); !wrong || kind != BadCasing {
+			t.Errorf("Expected \"%s\" to be a %s, found %s.", word, BadCasing, kind)
+		}
+	}
+
+	// All are correct when case is ignored
+	c.SetIgnoreUppercase(true)
+	if found := c.CheckList(dictionary, incorrect); len(found) != 0 {
+		t.Errorf("Expected no errors when ignoring case, found %v.", found)
+	}
+}
+
 // Test word checking using incorrect words.
 func TestCheckWordDoesntExist(t *testing.T) {
 	words := []string{
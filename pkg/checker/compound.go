@@ -0,0 +1,128 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// DefaultMinPartLength is the minimum length of a compound part used
+// if CompoundOptions.MinPartLength is not set.
+const DefaultMinPartLength = 3
+
+// CompoundOptions configures checking of compound words, words formed by
+// joining dictionary words, such as "Arbeitszeit" ("Arbeit", "s", "Zeit")
+// in German.
+type CompoundOptions struct {
+	MinPartLength int      // Minimum length of a part in bytes, DefaultMinPartLength if 0
+	Linking       []string // Linking morphemes allowed between parts, such as "s" or "en"
+	RequireFlags  bool     // If true, only words flagged as compound parts may be used
+}
+
+// SetCompound sets options of compound word checking. Words that are
+// not in the dictionary, but can be decomposed into dictionary words,
+// are accepted. Compound checking is disabled if opts is nil (default.)
+func (c *Checker) SetCompound(opts *CompoundOptions) {
+	c.compound = opts
+}
+
+// CheckCompound verifies a given word against a given Trie, returns true
+// if word is accepted by CheckWord, or if it can be decomposed into two
+// or more parts accepted by the given options.
+//
+// Parts are matched against dictionary entries regardless of case, but
+// the compound must be lowercase, Title-case, or ALL-CAPS. A part is
+// rejected if its entry is flagged loader.NoCompound, and, if
+// opts.RequireFlags is true, unless its entry is flagged as allowed in
+// the part's position (loader.CompoundBegin, loader.CompoundMiddle or
+// loader.CompoundEnd.)
+func CheckCompound(root *loader.Node, word string, opts CompoundOptions) bool {
+	if CheckWord(root, word) {
+		return true
+	}
+
+	if word != strings.ToLower(word) && word != strings.ToUpper(word) && !isTitle(word) {
+		return false
+	}
+
+	if opts.MinPartLength <= 0 {
+		opts.MinPartLength = DefaultMinPartLength
+	}
+
+	d := decomposition{
+		root:   root,
+		word:   strings.ToLower(word),
+		opts:   opts,
+		failed: make(map[int]bool),
+	}
+
+	return d.from(0)
+}
+
+// decomposition is a search for the parts of a compound word.
+type decomposition struct {
+	root   *loader.Node
+	word   string // Lowercase compound word
+	opts   CompoundOptions
+	failed map[int]bool // Offsets from which no decomposition exists
+}
+
+// from returns true if the word can be decomposed into parts starting
+// at the given offset, with at least two parts in total.
+func (d *decomposition) from(start int) bool {
+	if d.failed[start] {
+		return false
+	}
+
+	for end := start + d.opts.MinPartLength; end <= len(d.word); end++ {
+		if end == len(d.word) && start == 0 {
+			break // A single part is not a compound
+		}
+
+		if !d.isPart(start, end) {
+			continue
+		}
+
+		if end == len(d.word) || d.from(end) {
+			return true
+		}
+
+		for _, link := range d.opts.Linking {
+			if link != "" && end+len(link) < len(d.word) && strings.HasPrefix(d.word[end:], link) && d.from(end+len(link)) {
+				return true
+			}
+		}
+	}
+
+	d.failed[start] = true
+	return false
+}
+
+// isPart returns true if word[start:end] is a dictionary word allowed
+// as a compound part in its position.
+func (d *decomposition) isPart(start, end int) bool {
+	position := loader.CompoundMiddle
+	switch {
+	case start == 0:
+		position = loader.CompoundBegin
+	case end == len(d.word):
+		position = loader.CompoundEnd
+	}
+
+	for _, entry := range lookupFold(d.root, d.word[start:end]) {
+		if entry != strings.ToLower(entry) && !isTitle(entry) {
+			continue // Mixed-case entries must be matched exactly
+		}
+
+		flags := d.root.Find(entry).Flags()
+		if flags&loader.NoCompound != 0 {
+			continue
+		}
+
+		if !d.opts.RequireFlags || flags&position != 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package checker
+
+import (
+	"strings"
+)
+
+// confused returns a ConfusedWord error for word w at index i of a
+// line, with a fix that replaces it with suggestion.
+func confused(l line, w word, i int, suggestion string) SpellingError {
+	return SpellingError{
+		Word:   w.text,
+		Row:    l.row,
+		Col:    i,
+		Offset: w.start,
+		Kind:   ConfusedWord,
+		Fix:    &Fix{l.row, w.start, w.end(), withCase(w.text, suggestion)},
+	}
+}
+
+// withCase returns a lowercase word in the same case as another word,
+// either ALL-CAPS, Title-case, or lowercase.
+func withCase(as, word string) string {
+	switch {
+	case len(as) > 1 && as == strings.ToUpper(as):
+		return strings.ToUpper(word)
+	case len(as) > 0 && isUpper(as[0]):
+		return strings.ToUpper(word[:1]) + word[1:]
+	default:
+		return word
+	}
+}
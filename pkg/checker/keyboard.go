@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Costs of edits that are likely typos when a keyboard layout is set.
+// Transpositions are the most common typos, so they are the cheapest.
+const (
+	AdjacentKeyCost   = 0.5 // Cost of substituting a key with an adjacent key
+	TranspositionCost = 0.4 // Cost of swapping two adjacent characters
+)
+
+// Layout is a keyboard layout, used to weight edits when suggesting
+// corrections, so typos caused by hitting a key adjacent to the right
+// one ("wprd" for "word"), or swapping two keys ("teh" for "the"), rank
+// above other edits.
+type Layout struct {
+	name string
+	keys [128]key // Positions of keys, indexed by character
+}
+
+// key is a position of a key on a keyboard.
+type key struct {
+	row, col float64
+	ok       bool // False if the character has no key
+}
+
+// Supported keyboard layouts.
+var (
+	QWERTY = newLayout("qwerty", "qwertyuiop", "asdfghjkl;", "zxcvbnm,./")
+	AZERTY = newLayout("azerty", "azertyuiop", "qsdfghjklm", "wxcvbn,;:!")
+	Dvorak = newLayout("dvorak", "',.pyfgcrl", "aoeuidhtns", ";qjkxbmwvz")
+)
+
+// Layouts is a list of all supported keyboard layouts.
+var Layouts = []*Layout{QWERTY, AZERTY, Dvorak}
+
+// newLayout creates a layout from the three rows of letter keys of a
+// keyboard, top to bottom.
+func newLayout(name string, rows ...string) *Layout {
+	// Horizontal offsets of rows on a staggered keyboard
+	offsets := []float64{0, 0.25, 0.75}
+
+	l := &Layout{name: name}
+	for i, row := range rows {
+		for j := 0; j < len(row); j++ {
+			l.keys[row[j]] = key{float64(i), float64(j) + offsets[i], true}
+		}
+	}
+
+	return l
+}
+
+// ParseLayout returns the keyboard layout with the given name, as
+// returned by Layout.String.
+func ParseLayout(name string) (*Layout, error) {
+	for _, l := range Layouts {
+		if l.name == strings.ToLower(name) {
+			return l, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown keyboard layout %q", name)
+}
+
+// String returns the layout's name.
+func (l *Layout) String() string {
+	return l.name
+}
+
+// Adjacent returns true if keys of two lowercase characters are next to
+// each other on the keyboard, including diagonally.
+func (l *Layout) Adjacent(a, b byte) bool {
+	if a >= 128 || b >= 128 || a == b {
+		return false
+	}
+
+	ka, kb := l.keys[a], l.keys[b]
+	if !ka.ok || !kb.ok {
+		return false
+	}
+
+	return math.Hypot(ka.row-kb.row, ka.col-kb.col) <= 1.3
+}
+
+// substitution returns the cost of substituting lowercase character a
+// with b. A nil layout weights all substitutions equally.
+func (l *Layout) substitution(a, b byte) float64 {
+	switch {
+	case a == b:
+		return 0
+	case l != nil && l.Adjacent(a, b):
+		return AdjacentKeyCost
+	default:
+		return 1
+	}
+}
+
+// transposition returns the cost of swapping two adjacent characters.
+func (l *Layout) transposition() float64 {
+	if l == nil {
+		return 1
+	}
+
+	return TranspositionCost
+}
+
+// SetKeyboard sets the keyboard layout used to weight edits when
+// suggesting corrections, see Layout. A nil layout, the default,
+// weights all edits equally.
+func (c *Checker) SetKeyboard(l *Layout) {
+	c.keyboard = l
+}
@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"fmt"
+)
+
+// Kind is the kind of a spelling error.
+type Kind int
+
+// Kinds of spelling errors.
+const (
+	UnknownWord    Kind = iota // Word doesn't exist in the dictionary
+	RepeatedWord               // Word is a duplicate of the word before it
+	BadCasing                  // Word exists, but is incorrectly cased
+	ForbiddenWord              // Word is forbidden, see Checker.Forbid
+	Capitalization             // Word should start with an uppercase letter
+	ConfusedWord               // Word exists, but a similar word fits better
+)
+
+// Kinds is a list of all kinds of spelling errors.
+var Kinds = []Kind{
+	UnknownWord,
+	RepeatedWord,
+	BadCasing,
+	ForbiddenWord,
+	Capitalization,
+	ConfusedWord,
+}
+
+// String returns the kind's name.
+func (k Kind) String() string {
+	switch k {
+	case UnknownWord:
+		return "unknown-word"
+	case RepeatedWord:
+		return "repeated-word"
+	case BadCasing:
+		return "bad-casing"
+	case ForbiddenWord:
+		return "forbidden-word"
+	case Capitalization:
+		return "capitalization"
+	case ConfusedWord:
+		return "confused-word"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
+// DefaultSeverity returns the severity used for errors of the kind,
+// unless changed using Checker.SetSeverity.
+func (k Kind) DefaultSeverity() Severity {
+	switch k {
+	case RepeatedWord, BadCasing, Capitalization, ConfusedWord:
+		return Warning
+	default:
+		return Error
+	}
+}
+
+// ParseKind returns the kind with the given name, as returned by
+// Kind.String.
+func ParseKind(name string) (Kind, error) {
+	for _, k := range Kinds {
+		if k.String() == name {
+			return k, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown kind of error %q", name)
+}
+
+// Severity is the severity of a spelling error. Severities are ordered,
+// Info is the least severe, and Error is the most severe.
+type Severity int
+
+// Severities of spelling errors.
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// String returns the severity's name.
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// ParseSeverity returns the severity with the given name, as returned
+// by Severity.String.
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range []Severity{Info, Warning, Error} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown severity %q", name)
+}
@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// repeated checks whether the word at index i of a line's words is a
+// duplicate of the word before it, which can be the last word of the
+// previous line. Returns a RepeatedWord error, with a fix removing the
+// duplicate, and true if it is.
+func repeated(l line, words []word, i int) (SpellingError, bool) {
+	w := words[i]
+	if !hasLetter(w.text) {
+		return SpellingError{}, false
+	}
+
+	err := SpellingError{Word: w.text, Row: l.row, Col: i, Offset: w.start, Kind: RepeatedWord}
+	if i > 0 {
+		prev := words[i-1]
+		if !strings.EqualFold(prev.text, w.text) || !isSpace(l.text[prev.end():w.start]) {
+			return SpellingError{}, false
+		}
+
+		// Remove the duplicate and whitespace before it
+		err.Fix = &Fix{l.row, prev.end(), w.end(), ""}
+		return err, true
+	}
+
+	if l.prev == "" || !strings.EqualFold(l.prev, w.text) || !isSpace(l.text[:w.start]) {
+		return SpellingError{}, false
+	}
+
+	// Remove the duplicate and whitespace after it
+	end := w.end()
+	for end < len(l.text) && unicode.IsSpace(rune(l.text[end])) {
+		end++
+	}
+
+	err.Fix = &Fix{l.row, w.start, end, ""}
+	return err, true
+}
+
+// isSpace returns true if text consists only of whitespace.
+func isSpace(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+// hasLetter returns true if text contains at least one letter.
+func hasLetter(text string) bool {
+	return strings.IndexFunc(text, unicode.IsLetter) >= 0
+}
@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// abbreviations is a set of common abbreviations that end with a dot,
+// but don't end sentences.
+var abbreviations = map[string]bool{
+	"mr":     true,
+	"mrs":    true,
+	"ms":     true,
+	"dr":     true,
+	"prof":   true,
+	"sr":     true,
+	"jr":     true,
+	"st":     true,
+	"vs":     true,
+	"fig":    true,
+	"approx": true,
+}
+
+// capitalization checks whether the word at index i of a line's words
+// should start with an uppercase letter, because it starts a sentence
+// or is a proper noun. Returns a Capitalization error, with a fix that
+// capitalizes the word, and true if it should.
+func capitalization(root *loader.Node, l line, words []word, i int) (SpellingError, bool) {
+	w := words[i]
+
+	// Only check lowercase words, others are either correctly cased, or
+	// intentionally mixed-case
+	if b := w.text[0]; b < 'a' || b > 'z' || w.text != strings.ToLower(w.text) {
+		return SpellingError{}, false
+	}
+
+	start := l.start
+	if i > 0 {
+		prev := words[i-1]
+		start = endsSentence(prev.text, l.text[prev.end():w.start], false)
+	}
+
+	if !start && !properNoun(root, w.text) {
+		return SpellingError{}, false
+	}
+
+	return SpellingError{
+		Word:   w.text,
+		Row:    l.row,
+		Col:    i,
+		Offset: w.start,
+		Kind:   Capitalization,
+		Fix:    &Fix{l.row, w.start, w.start + 1, strings.ToUpper(w.text[:1])},
+	}, true
+}
+
+// properNoun returns true if a lowercase word only exists in the given
+// Trie in Title-case.
+func properNoun(root *loader.Node, word string) bool {
+	if root.Contains(word) {
+		return false
+	}
+
+	for _, entry := range lookupFold(root, word) {
+		if isTitle(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endsSentence returns true if gap, the text following word prev, ends
+// a sentence. eol is true if gap is at the end of a line.
+func endsSentence(prev, gap string, eol bool) bool {
+	i := strings.IndexAny(gap, ".!?")
+	if i < 0 || strings.Contains(gap, "...") {
+		return false
+	}
+
+	// Terminators must be followed by whitespace, as in "3.5" or "a.m."
+	// no sentence ends
+	if !eol && strings.IndexFunc(gap[i:], unicode.IsSpace) < 0 {
+		return false
+	}
+
+	if gap[i] == '.' && isAbbreviation(prev) {
+		return false
+	}
+
+	return true
+}
+
+// isAbbreviation returns true if word is an initial, such as "J" in
+// "J. Smith", or a common abbreviation.
+func isAbbreviation(word string) bool {
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsLetter(r)
+	}
+
+	return abbreviations[strings.ToLower(word)]
+}
+
+// lineEnd returns the last word of a line if only whitespace follows
+// it, or an empty string otherwise, splitting words as split does.
+// sentence is true if the line is blank, or ends a sentence.
+func lineEnd(text string, wordEnd func(c rune) bool, apostrophes bool) (last string, sentence bool) {
+	end := strings.LastIndexFunc(text, func(c rune) bool {
+		return !wordEnd(c) && !unicode.IsSpace(c)
+	})
+
+	if end < 0 {
+		return "", isSpace(text)
+	}
+
+	_, size := utf8.DecodeRuneInString(text[end:])
+	end += size
+
+	start := end
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if separates(text, start-size, r, wordEnd, apostrophes) {
+			break
+		}
+
+		start -= size
+	}
+
+	word, tail := text[start:end], text[end:]
+	if isSpace(tail) {
+		last = word
+	}
+
+	return last, endsSentence(word, tail, true)
+}
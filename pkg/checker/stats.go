@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats are statistics of checked text, see StreamStats.
+type Stats struct {
+	Words   int           // Number of checked words, excluding ignored words
+	Errors  int           // Number of errors found
+	Elapsed time.Duration // Time spent checking
+
+	unique     map[string]bool // Checked words, case-sensitive
+	misspelled map[string]int  // Occurrences of misspelled words
+}
+
+// Misspelling is a misspelled word, and its number of occurrences.
+type Misspelling struct {
+	Word  string
+	Count int
+}
+
+// NewStats returns new, empty Stats, such as to add statistics of
+// multiple texts to using Add.
+func NewStats() *Stats {
+	return &Stats{
+		unique:     make(map[string]bool),
+		misspelled: make(map[string]int),
+	}
+}
+
+// Unique returns the number of distinct checked words. Words are
+// compared as written, so "The" and "the" are counted as two words.
+func (s *Stats) Unique() int {
+	return len(s.unique)
+}
+
+// Rate returns the number of errors per checked word, 0 if no words
+// were checked.
+func (s *Stats) Rate() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Words)
+}
+
+// Misspellings returns up to n most frequent misspelled words, words of
+// unknown-word and bad-casing errors, sorted by their occurrences, then
+// alphabetically. All are returned if n is smaller than 1.
+func (s *Stats) Misspellings(n int) []Misspelling {
+	misspellings := make([]Misspelling, 0, len(s.misspelled))
+	for word, count := range s.misspelled {
+		misspellings = append(misspellings, Misspelling{word, count})
+	}
+
+	sort.Slice(misspellings, func(i, j int) bool {
+		if misspellings[i].Count != misspellings[j].Count {
+			return misspellings[i].Count > misspellings[j].Count
+		}
+
+		return misspellings[i].Word < misspellings[j].Word
+	})
+
+	if n > 0 && len(misspellings) > n {
+		misspellings = misspellings[:n]
+	}
+
+	return misspellings
+}
+
+// Add adds statistics of other to s, as if their texts were checked
+// together.
+func (s *Stats) Add(other *Stats) {
+	s.Words += other.Words
+	s.Errors += other.Errors
+	s.Elapsed += other.Elapsed
+
+	for word := range other.unique {
+		s.unique[word] = true
+	}
+
+	for word, count := range other.misspelled {
+		s.misspelled[word] += count
+	}
+}
+
+// SetErrors replaces counted errors with given errors, such as errors
+// left after filtering the ones found. Words aren't changed, so Rate is
+// then relative to all checked words.
+func (s *Stats) SetErrors(errors []SpellingError) {
+	s.Errors = 0
+	s.misspelled = make(map[string]int)
+	for _, e := range errors {
+		s.addError(e)
+	}
+}
+
+// addWords counts checked words.
+func (s *Stats) addWords(words []word) {
+	s.Words += len(words)
+	for _, w := range words {
+		s.unique[w.text] = true
+	}
+}
+
+// addError counts a found error.
+func (s *Stats) addError(e SpellingError) {
+	s.Errors++
+	if e.Kind == UnknownWord || e.Kind == BadCasing {
+		s.misspelled[e.Word]++
+	}
+}
@@ -0,0 +1,263 @@
+package checker
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+	"github.com/sudo-sturbia/gocheck/v3/pkg/phonetic"
+)
+
+// DefaultMaxSuggestions is the default number of suggestions returned
+// by Checker.Suggest.
+const DefaultMaxSuggestions = 5
+
+// SetMaxSuggestions sets the maximum number of suggestions returned by
+// Suggest. A value smaller than 1 restores the default.
+func (c *Checker) SetMaxSuggestions(n int) {
+	if n < 1 {
+		n = DefaultMaxSuggestions
+	}
+
+	c.maxSuggestions = n
+}
+
+// SetPhoneticIndex sets an index of sound-alike words, built from the
+// dictionary used when checking (see phonetic.NewIndex.) Suggest adds
+// words that sound like a misspelled word to its suggestions, and ranks
+// them above other words at a similar distance. A nil index, the
+// default, disables phonetic matching.
+func (c *Checker) SetPhoneticIndex(idx *phonetic.Index) {
+	c.sounds = idx
+}
+
+// candidate is a dictionary word similar to a misspelled word.
+type candidate struct {
+	word      string
+	distance  float64 // Edit distance to the misspelled word
+	frequency int     // Frequency of the word in the dictionary
+	phonetic  bool    // True if the word sounds like the misspelled word
+	score     float64 // Rank of the candidate, lower is better
+}
+
+// Suggest returns a list of words of a given Trie that are similar to
+// word, most likely corrections first. Candidates are words within a
+// small edit distance (insertions, deletions, substitutions, and
+// transpositions) of word, compared case-insensitively, where edits
+// are cheaper if they are likely typos on a set keyboard, and words that
+// sound like it if a phonetic index is set. Candidates are ranked by
+// combining their distance with their frequency, if the trie was loaded
+// with frequencies, so a frequent word is preferred over a rare one at
+// the same distance, and with whether they sound like word. Suggestions
+// are returned in word's case if they are lowercase dictionary entries.
+func (c *Checker) Suggest(root *loader.Node, word string) []string {
+	candidates := c.candidates(root, word)
+	rank(candidates)
+
+	suggestions := make([]string, 0, c.maxSuggestions)
+	seen := make(map[string]bool)
+	for _, cand := range candidates {
+		suggestion := cand.word
+		if suggestion == strings.ToLower(suggestion) {
+			suggestion = withCase(word, suggestion)
+		}
+
+		if suggestion == word || seen[suggestion] {
+			continue
+		}
+
+		seen[suggestion] = true
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == c.maxSuggestions {
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// maxDistance returns the maximum edit distance of candidates for a
+// word. Short words allow less edits, otherwise anything is similar.
+func maxDistance(word string) float64 {
+	if len(word) <= 3 {
+		return 1
+	}
+
+	return 2
+}
+
+// candidates returns all words of a given Trie within the maximum edit
+// distance of word.
+func (c *Checker) candidates(root *loader.Node, word string) []candidate {
+	s := &search{
+		word:   strings.ToLower(word),
+		max:    maxDistance(word),
+		layout: c.keyboard,
+	}
+
+	// Distance of an empty prefix to each prefix of word
+	row := make([]float64, len(s.word)+1)
+	for i := range row {
+		row[i] = float64(i)
+	}
+
+	s.walk(root, make([]byte, 0, len(word)+int(s.max)), 0, row, nil)
+	if c.sounds == nil {
+		return s.found
+	}
+
+	// Add sound-alike words, unless they are too different
+	found := make(map[string]int)
+	for i, cand := range s.found {
+		found[cand.word] = i
+	}
+
+	for _, match := range c.sounds.Lookup(word) {
+		if i, ok := found[match.Word]; ok {
+			s.found[i].phonetic = true
+			continue
+		}
+
+		distance := editDistance(c.keyboard, s.word, strings.ToLower(match.Word))
+		if distance <= maxPhoneticDistance(word) {
+			s.found = append(s.found, candidate{
+				word:      match.Word,
+				distance:  distance,
+				frequency: match.Frequency,
+				phonetic:  true,
+			})
+		}
+	}
+
+	return s.found
+}
+
+// maxPhoneticDistance returns the maximum edit distance of sound-alike
+// candidates for a word, which is more lenient than maxDistance.
+func maxPhoneticDistance(word string) float64 {
+	return maxDistance(word) + float64(len(word))/3
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string
+// alignment) distance between two lowercase words, with substitutions
+// and transpositions weighted using a keyboard layout, which may be
+// nil.
+func editDistance(layout *Layout, a, b string) float64 {
+	rows := make([][]float64, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]float64, len(b)+1)
+		rows[i][0] = float64(i)
+	}
+
+	for j := range rows[0] {
+		rows[0][j] = float64(j)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := layout.substitution(a[i-1], b[j-1])
+			rows[i][j] = math.Min(math.Min(rows[i-1][j]+1, rows[i][j-1]+1), rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && a[i-1] != a[i-2] {
+				rows[i][j] = math.Min(rows[i][j], rows[i-2][j-2]+layout.transposition())
+			}
+		}
+	}
+
+	return rows[len(a)][len(b)]
+}
+
+// search is a search of a trie for words within an edit distance.
+type search struct {
+	word   string  // Lowercase word to find candidates for
+	max    float64 // Maximum distance of a candidate
+	layout *Layout // Keyboard layout weighting edits, nil if unknown
+	found  []candidate
+}
+
+// walk, recursively, computes distances between prefixes of the trie
+// and prefixes of the searched word using the Damerau-Levenshtein
+// algorithm, one row per trie node. prefix is the path to node, last
+// is the lowercase character at its end, and row and prevRow are the
+// rows of node and its parent.
+func (s *search) walk(node *loader.Node, prefix []byte, last byte, row, prevRow []float64) {
+	n := len(s.word)
+	if node.IsWord() && row[n] <= s.max {
+		s.found = append(s.found, candidate{word: string(prefix), distance: row[n], frequency: node.Frequency()})
+	}
+
+	for i := 0; i < loader.PrintableASCII; i++ {
+		b := byte(i + loader.FirstPrintableASCII)
+		next := node.Child(b)
+		if next == nil {
+			continue
+		}
+
+		lower := toLower(b)
+
+		nextRow := make([]float64, n+1)
+		nextRow[0] = row[0] + 1
+		best := nextRow[0]
+		for j := 1; j <= n; j++ {
+			nextRow[j] = math.Min(math.Min(
+				row[j]+1,       // Insertion of b
+				nextRow[j-1]+1, // Deletion of word[j-1]
+			), row[j-1]+s.layout.substitution(lower, s.word[j-1]))
+
+			// Transposition of two adjacent characters
+			if prevRow != nil && j > 1 && lower == s.word[j-2] && last == s.word[j-1] && lower != last {
+				nextRow[j] = math.Min(nextRow[j], prevRow[j-2]+s.layout.transposition())
+			}
+
+			best = math.Min(best, nextRow[j])
+		}
+
+		// No word under next can be within the maximum distance
+		if best > s.max {
+			continue
+		}
+
+		s.walk(next, append(prefix, b), lower, nextRow, row)
+	}
+}
+
+// rank sorts candidates, most likely corrections first. A candidate's
+// score is its edit distance, reduced by up to 1 according to its
+// frequency relative to the most frequent candidate, and by 1 if it
+// sounds like the misspelled word.
+func rank(candidates []candidate) {
+	max := 0
+	for _, cand := range candidates {
+		if cand.frequency > max {
+			max = cand.frequency
+		}
+	}
+
+	for i := range candidates {
+		candidates[i].score = candidates[i].distance
+		if max > 0 {
+			candidates[i].score -= math.Log1p(float64(candidates[i].frequency)) / math.Log1p(float64(max))
+		}
+
+		if candidates[i].phonetic {
+			candidates[i].score--
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+
+		return candidates[i].word < candidates[j].word
+	})
+}
+
+// toLower returns the lowercase variant of an ASCII character.
+func toLower(b byte) byte {
+	if isUpper(b) {
+		return b - 'A' + 'a'
+	}
+
+	return b
+}
@@ -0,0 +1,217 @@
+// Package confusion implements detection of real-word errors, words
+// that exist in a dictionary, but are likely confused with a similar
+// word, such as "their" and "there", or "its" and "it's".
+//
+// A Set is loaded from a text file containing groups of confusable
+// words, and context rules that decide which word of a group fits a
+// given context.
+//
+//	# A group is a list of words separated by spaces.
+//	their there they're
+//
+//	# A rule is a word followed by a colon, and a pattern of words
+//	# surrounding it, where "_" marks the word's position.
+//	their: _ own
+//	there: over _
+//
+// A word of a group is considered confused if rules of another word of
+// its group match its context better than its own rules. Rules with
+// longer patterns are stronger.
+package confusion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Slot marks the position of a rule's word in its pattern.
+const Slot = "_"
+
+// Set is a set of groups of confusable words, and context rules.
+type Set struct {
+	groups map[string][]string // Maps a word to the words of its group
+	rules  map[string][]rule   // Maps a word to rules in which it fits
+}
+
+// rule is a pattern of words that surround a word of a group.
+type rule struct {
+	before []string // Words expected before the slot
+	after  []string // Words expected after the slot
+}
+
+// weight returns the strength of the rule.
+func (r rule) weight() int {
+	return len(r.before) + len(r.after)
+}
+
+// matches returns true if the rule matches the context of the word at
+// index i of words.
+func (r rule) matches(words []string, i int) bool {
+	if i < len(r.before) || i+len(r.after) >= len(words) {
+		return false
+	}
+
+	for j, word := range r.before {
+		if words[i-len(r.before)+j] != word {
+			return false
+		}
+	}
+
+	for j, word := range r.after {
+		if words[i+1+j] != word {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LoadFile creates a new Set using groups and rules from the text file
+// at given path. Returns an error if reading, or parsing fails.
+func LoadFile(path string) (*Set, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse creates a new Set using groups and rules read from r. See the
+// package's documentation for the format.
+func Parse(r io.Reader) (*Set, error) {
+	set := &Set{make(map[string][]string), make(map[string][]rule)}
+
+	type pending struct {
+		row  int
+		word string
+		rule rule
+	}
+
+	// Rules are added after all groups are known
+	var rules []pending
+
+	scanner := bufio.NewScanner(r)
+	for row := 1; scanner.Scan(); row++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		colon := strings.Index(text, ":")
+		if colon < 0 {
+			if err := set.addGroup(strings.Fields(strings.ToLower(text))); err != nil {
+				return nil, fmt.Errorf("line %d: %v", row, err)
+			}
+
+			continue
+		}
+
+		word := strings.ToLower(strings.TrimSpace(text[:colon]))
+		r, err := parseRule(strings.Fields(strings.ToLower(text[colon+1:])))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", row, err)
+		}
+
+		rules = append(rules, pending{row, word, r})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range rules {
+		if _, ok := set.groups[p.word]; !ok {
+			return nil, fmt.Errorf("line %d: %q is not in a group", p.row, p.word)
+		}
+
+		set.rules[p.word] = append(set.rules[p.word], p.rule)
+	}
+
+	return set, nil
+}
+
+// addGroup adds a group of confusable words to the set.
+func (s *Set) addGroup(words []string) error {
+	if len(words) < 2 {
+		return fmt.Errorf("a group must contain at least two words")
+	}
+
+	for _, word := range words {
+		if _, ok := s.groups[word]; ok {
+			return fmt.Errorf("%q is in more than one group", word)
+		}
+
+		s.groups[word] = words
+	}
+
+	return nil
+}
+
+// parseRule parses a pattern of words containing exactly one slot.
+func parseRule(pattern []string) (rule, error) {
+	slot := -1
+	for i, word := range pattern {
+		if word != Slot {
+			continue
+		}
+
+		if slot >= 0 {
+			return rule{}, fmt.Errorf("pattern contains more than one %q", Slot)
+		}
+
+		slot = i
+	}
+
+	if slot < 0 {
+		return rule{}, fmt.Errorf("pattern doesn't contain %q", Slot)
+	}
+
+	if len(pattern) == 1 {
+		return rule{}, fmt.Errorf("pattern contains no words")
+	}
+
+	return rule{pattern[:slot], pattern[slot+1:]}, nil
+}
+
+// Confusable returns true if word, in lowercase, belongs to a group.
+func (s *Set) Confusable(word string) bool {
+	_, ok := s.groups[word]
+	return ok
+}
+
+// Check checks the word at index i of a list of lowercase words, such
+// as a sentence, against its context. Returns the word of its group
+// that fits the context best, and true if it's not the checked word.
+func (s *Set) Check(words []string, i int) (string, bool) {
+	group, ok := s.groups[words[i]]
+	if !ok {
+		return "", false
+	}
+
+	best, bestScore := words[i], s.score(words[i], words, i)
+	for _, other := range group {
+		if score := s.score(other, words, i); score > bestScore {
+			best, bestScore = other, score
+		}
+	}
+
+	return best, best != words[i]
+}
+
+// score returns the sum of weights of a word's rules that match the
+// context at index i of words.
+func (s *Set) score(word string, words []string, i int) int {
+	score := 0
+	for _, r := range s.rules[word] {
+		if r.matches(words, i) {
+			score += r.weight()
+		}
+	}
+
+	return score
+}
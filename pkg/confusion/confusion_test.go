@@ -0,0 +1,87 @@
+package confusion
+
+import (
+	"strings"
+	"testing"
+)
+
+// Set used by tests.
+const testSet = `
+# Comment
+their there they're
+their: _ own
+there: over _
+there: _ is
+they're: _ going
+
+its it's
+it's: _ a
+its: _ own
+`
+
+// Test checking words against their context.
+func TestCheck(t *testing.T) {
+	set, err := Parse(strings.NewReader(testSet))
+	if err != nil {
+		t.Fatalf("Parsing failed: %v.", err)
+	}
+
+	tests := []struct {
+		sentence string
+		i        int
+		expected string
+		confused bool
+	}{
+		{"they have there own house", 2, "their", true},
+		{"they have their own house", 2, "their", false},
+		{"it is over their", 3, "there", true},
+		{"their is a cat", 0, "there", true},
+		{"their going home", 0, "they're", true},
+		{"its a nice day", 0, "it's", true},
+		{"it's a nice day", 0, "it's", false},
+		{"the dog wagged it's own tail", 3, "its", true},
+		{"we saw their dog", 2, "their", false},
+		{"a nice day", 1, "", false},
+	}
+
+	for _, test := range tests {
+		words := strings.Fields(test.sentence)
+		suggestion, confused := set.Check(words, test.i)
+		if confused != test.confused || (confused && suggestion != test.expected) {
+			t.Errorf("Checking %q in %q, expected (%q, %v), found (%q, %v).",
+				words[test.i], test.sentence, test.expected, test.confused, suggestion, confused)
+		}
+	}
+}
+
+// Test parsing invalid sets.
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"their",
+		"their there\nthere their",
+		"their there\nthey're: _ going",
+		"their there\ntheir: own",
+		"their there\ntheir: _ own _",
+		"their there\ntheir: _",
+	}
+
+	for _, text := range invalid {
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("Expected an error parsing %q.", text)
+		}
+	}
+}
+
+// Test loading the set distributed with gocheck.
+func TestLoadFile(t *testing.T) {
+	set, err := LoadFile("../../data/confusables.txt")
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	for _, word := range []string{"their", "there", "they're", "its", "it's", "affect", "effect"} {
+		if !set.Confusable(word) {
+			t.Errorf("%q should be confusable.", word)
+		}
+	}
+}
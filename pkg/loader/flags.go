@@ -0,0 +1,78 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flags are properties of a dictionary word, written in dictionary
+// files as a column of letters following the word (and its frequency,
+// if any.)
+//
+//	haus	120	BM
+//	tuer	E
+type Flags uint8
+
+// Supported flags and the letters representing them.
+const (
+	CompoundBegin  Flags = 1 << iota // "B", word may begin a compound
+	CompoundMiddle                   // "M", word may be in the middle of a compound
+	CompoundEnd                      // "E", word may end a compound
+	NoCompound                       // "N", word may never be part of a compound
+
+	CompoundAny = CompoundBegin | CompoundMiddle | CompoundEnd // "C", word may be anywhere in a compound
+)
+
+// flagLetters maps flag letters to flags.
+var flagLetters = []struct {
+	letter byte
+	flag   Flags
+}{
+	{'B', CompoundBegin},
+	{'M', CompoundMiddle},
+	{'E', CompoundEnd},
+	{'N', NoCompound},
+}
+
+// ParseFlags parses a string of flag letters. Returns an error if a
+// letter is not a known flag.
+func ParseFlags(s string) (Flags, error) {
+	var flags Flags
+	for i := 0; i < len(s); i++ {
+		if s[i] == 'C' {
+			flags |= CompoundAny
+			continue
+		}
+
+		known := false
+		for _, l := range flagLetters {
+			if s[i] == l.letter {
+				flags |= l.flag
+				known = true
+			}
+		}
+
+		if !known {
+			return 0, fmt.Errorf("unknown flag %q", s[i])
+		}
+	}
+
+	return flags, nil
+}
+
+// String returns the letters representing flags.
+func (f Flags) String() string {
+	var b strings.Builder
+	if f&CompoundAny == CompoundAny {
+		b.WriteByte('C')
+		f &^= CompoundAny
+	}
+
+	for _, l := range flagLetters {
+		if f&l.flag != 0 {
+			b.WriteByte(l.letter)
+		}
+	}
+
+	return b.String()
+}
@@ -1,12 +1,25 @@
 // Package loader implements functions for loading strings into a trie
 // to be used as a dictionary.
+//
+// Dictionary files contain one word per line, optionally followed by a
+// tab and the word's frequency (for example, the number of times it
+// occurs in a corpus.) Frequencies are used to rank suggestions.
+//
+//	the	56271872
+//	thee	10364
+//
+// A word may also be followed by a column of flags, see Flags.
 package loader
 
 import (
-	"bufio"
+	"fmt"
+	"io"
 	"log"
 	"os"
-	"unicode"
+	"strconv"
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/internal/lines"
 )
 
 // Number of printable ASCII characters and their starting position.
@@ -17,11 +30,14 @@ const (
 
 // Node represents a node in a trie.
 type Node struct {
-	children [PrintableASCII]*Node // Children nodes
-	isWord   bool                  // True if node marks a word ending, false otherwise
+	children  [PrintableASCII]*Node // Children nodes
+	isWord    bool                  // True if node marks a word ending, false otherwise
+	frequency int                   // Frequency of the word ending at node, 0 if unknown
+	flags     Flags                 // Flags of the word ending at node
 }
 
-// Children returns an array of children of a Node.
+// Children returns a copy of the array of children of a Node. See
+// also Child and Walk.
 func (n *Node) Children() [PrintableASCII]*Node {
 	return n.children
 }
@@ -31,32 +47,172 @@ func (n *Node) IsWord() bool {
 	return n.isWord
 }
 
+// Frequency returns the frequency of the word ending at node, or 0 if
+// it's unknown or node doesn't mark a word ending.
+func (n *Node) Frequency() int {
+	return n.frequency
+}
+
+// Flags returns the flags of the word ending at node.
+func (n *Node) Flags() Flags {
+	return n.flags
+}
+
+// Options configures reading of dictionary files.
+type Options struct {
+	MaxLineLength int  // Maximum length of a line in bytes, 0 for no limit
+	Validate      bool // Report problems in dictionary files, see ValidationError
+}
+
+// LineLengthError is returned when lines longer than a set maximum
+// are found while reading text. Long lines are skipped, and reading
+// continues, so it's returned alongside a valid result.
+type LineLengthError struct {
+	Max  int   // Maximum length of a line
+	Rows []int // Rows of lines exceeding the maximum, starting at 0
+}
+
+// Error returns a description of the error.
+func (e *LineLengthError) Error() string {
+	return fmt.Sprintf("%d line(s) longer than %d bytes at rows %v", len(e.Rows), e.Max, e.Rows)
+}
+
 // LoadFile creates a new trie using words from text file at the
 // given path. Returns a pointer to trie's root.
 func LoadFile(path string) *Node {
-	// Open dictionary file
-	file, err := os.Open(path)
-
+	root, err := LoadFileOptions(path, Options{})
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	return root
+}
+
+// LoadFileOptions creates a new trie using words from text file at the
+// given path, and given options. Returns a pointer to trie's root, and
+// an error if file reading fails. If lines longer than the maximum are
+// found, they are skipped, and a *LineLengthError is returned alongside
+// the trie.
+func LoadFileOptions(path string, opts Options) (*Node, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
-	// Load words from file to a trie
+	return LoadReader(file, opts)
+}
+
+// LoadReader creates a new trie using words read from r, one word per
+// line, optionally followed by tab separated frequency and flags. Blank
+// lines, and words containing bytes other than printable ASCII, are
+// skipped, and columns that aren't a valid frequency or flags are
+// ignored. Returns an error if reading fails. See also LoadFileOptions.
+//
+// If opts.Validate is set, words are trimmed of surrounding whitespace,
+// lines with invalid columns are skipped, and all problems found,
+// including long lines, are returned as a *ValidationError alongside
+// the trie.
+func LoadReader(r io.Reader, opts Options) (*Node, error) {
 	root := new(Node)
 
-	fileScanner := bufio.NewScanner(file)
-	for fileScanner.Scan() {
-		word := fileScanner.Text()
+	var (
+		long     []int
+		problems []Problem
+	)
+	reader := lines.NewReader(r, opts.MaxLineLength)
+	for row := 0; ; row++ {
+		line, isLong, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
 
-		root = LoadWord(root, word)
+		if err != nil {
+			return nil, err
+		}
+
+		if isLong {
+			long = append(long, row)
+			problems = append(problems, Problem{Row: row, Kind: LongLine})
+			continue
+		}
+
+		if opts.Validate {
+			word, frequency, flags, ok := validate(root, row, line, &problems)
+			if ok {
+				root = LoadEntry(root, word, frequency, flags)
+			}
+
+			continue
+		}
+
+		word, frequency, flags, _ := parseLine(line, false)
+		if word != "" && printable(word) {
+			root = LoadEntry(root, word, frequency, flags)
+		}
 	}
 
-	if err := fileScanner.Err(); err != nil {
-		log.Fatal(err)
+	if opts.Validate && len(problems) != 0 {
+		return root, &ValidationError{problems}
 	}
 
-	return root
+	if len(long) != 0 {
+		return root, &LineLengthError{opts.MaxLineLength, long}
+	}
+
+	return root, nil
+}
+
+// parseLine parses a line of a dictionary file, returns the line's
+// word, frequency and flags. Columns following the word are a
+// frequency if numeric, and flags otherwise. If strict is false,
+// invalid and extra columns are ignored instead of returning an error.
+func parseLine(line string, strict bool) (string, int, Flags, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) > 3 {
+		if strict {
+			return "", 0, 0, fmt.Errorf("expected a word, a frequency and flags, found %d columns", len(fields))
+		}
+
+		fields = fields[:3]
+	}
+
+	var (
+		frequency    int
+		flags        Flags
+		hasFrequency bool
+		hasFlags     bool
+	)
+	for _, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "":
+		case field[0] == '-' || (field[0] >= '0' && field[0] <= '9'):
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 0 || hasFrequency {
+				if !strict {
+					continue
+				}
+
+				return "", 0, 0, fmt.Errorf("invalid frequency %q", field)
+			}
+
+			frequency, hasFrequency = n, true
+		default:
+			f, err := ParseFlags(field)
+			if err != nil || hasFlags {
+				if !strict {
+					continue
+				}
+
+				return "", 0, 0, fmt.Errorf("invalid flags %q", field)
+			}
+
+			flags, hasFlags = f, true
+		}
+	}
+
+	return fields[0], frequency, flags, nil
 }
 
 // LoadList creates a new trie using given string list. Returns a pointer
@@ -70,12 +226,113 @@ func LoadList(list []string) *Node {
 	return root
 }
 
-// LoadWord loads a word into trie. Returns a pointer to trie's root
-// node.
+// LoadWord loads a word into trie. Words containing bytes other than
+// printable ASCII are not loaded. Returns a pointer to trie's root node.
 func LoadWord(root *Node, word string) *Node {
+	if !printable(word) {
+		return root
+	}
+
 	return recLoad(root, word, 0)
 }
 
+// LoadWordFrequency loads a word with a given frequency into trie. If
+// the word was already loaded, frequency is added to its frequency.
+// Returns a pointer to trie's root node.
+func LoadWordFrequency(root *Node, word string, frequency int) *Node {
+	return LoadEntry(root, word, frequency, 0)
+}
+
+// LoadEntry loads a word with a given frequency and flags into trie. If
+// the word was already loaded, frequency is added to its frequency, and
+// flags to its flags. Returns a pointer to trie's root node.
+func LoadEntry(root *Node, word string, frequency int, flags Flags) *Node {
+	root = LoadWord(root, word)
+	if node := root.Find(word); node != nil {
+		node.frequency += frequency
+		node.flags |= flags
+	}
+
+	return root
+}
+
+// RemoveWord removes a word from trie, and prunes branches left without
+// words. Returns a pointer to trie's root node.
+func RemoveWord(root *Node, word string) *Node {
+	recRemove(root, word, 0)
+	return root
+}
+
+// recRemove removes a word from a trie recursively. Returns true if
+// root is left without words, and can be pruned.
+func recRemove(root *Node, word string, whichChar int) bool {
+	if whichChar == len(word) {
+		root.isWord = false
+		root.frequency = 0
+		root.flags = 0
+		return root.empty()
+	}
+
+	if word[whichChar] < FirstPrintableASCII || word[whichChar] >= FirstPrintableASCII+PrintableASCII {
+		return false
+	}
+
+	next := root.children[word[whichChar]-FirstPrintableASCII]
+	if next == nil {
+		return false
+	}
+
+	if recRemove(next, word, whichChar+1) {
+		root.children[word[whichChar]-FirstPrintableASCII] = nil
+	}
+
+	return root.empty()
+}
+
+// empty returns true if node has no children, and doesn't mark a word
+// ending.
+func (n *Node) empty() bool {
+	if n.isWord {
+		return false
+	}
+
+	for _, child := range &n.children {
+		if child != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Contains returns true if word exists in trie as is.
+func (n *Node) Contains(word string) bool {
+	node := n.Find(word)
+	return node != nil && node.isWord
+}
+
+// Len returns the number of words in trie, that is words ending at or
+// under node.
+func (n *Node) Len() int {
+	count := 0
+	if n.isWord {
+		count++
+	}
+
+	for _, child := range &n.children {
+		if child != nil {
+			count += child.Len()
+		}
+	}
+
+	return count
+}
+
+// Clear removes all words from trie.
+func (n *Node) Clear() {
+	*n = Node{}
+}
+
 // recLoad loads a word into a trie recursively.
 func recLoad(root *Node, word string, whichChar int) *Node {
 	// If end of word
@@ -89,10 +346,7 @@ func recLoad(root *Node, word string, whichChar int) *Node {
 		root.children[word[whichChar]-FirstPrintableASCII] = new(Node)
 	}
 
-	// If character is printable ASCII
-	if word[whichChar] >= 32 && word[whichChar] <= unicode.MaxASCII {
-		root.children[word[whichChar]-FirstPrintableASCII] = recLoad(root.children[word[whichChar]-FirstPrintableASCII], word, whichChar+1)
-	}
+	root.children[word[whichChar]-FirstPrintableASCII] = recLoad(root.children[word[whichChar]-FirstPrintableASCII], word, whichChar+1)
 
 	return root
 }
@@ -1,6 +1,9 @@
 package loader
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -60,6 +63,372 @@ func TestLoadingFromList(t *testing.T) {
 	}
 }
 
+// Test loading from a reader containing very long lines.
+func TestLoadingLongLines(t *testing.T) {
+	long := strings.Repeat("a", 100000)
+	root, err := LoadReader(strings.NewReader("short\n"+long+"\nword\n"), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	for _, word := range []string{"short", long, "word"} {
+		if !isWordLoaded(root, word, 0) {
+			t.Errorf("Word of length %d was not loaded.\n", len(word))
+		}
+	}
+}
+
+// Test skipping lines longer than a maximum.
+func TestLoadingMaxLineLength(t *testing.T) {
+	long := strings.Repeat("a", 100)
+	root, err := LoadReader(strings.NewReader("short\n"+long+"\nword\n"), Options{MaxLineLength: 10})
+
+	var lengthErr *LineLengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("Expected a LineLengthError, found %v.", err)
+	}
+
+	if len(lengthErr.Rows) != 1 || lengthErr.Rows[0] != 1 {
+		t.Errorf("Expected long line at row 1, found %v.", lengthErr.Rows)
+	}
+
+	if !isWordLoaded(root, "short", 0) || !isWordLoaded(root, "word", 0) {
+		t.Errorf("Short lines were not loaded.\n")
+	}
+}
+
+// Test loading words with frequencies.
+func TestLoadingFrequencies(t *testing.T) {
+	text := "the\t1000\nthee\t5\nword\nword\t7\nword\t3\nempty\t\n"
+	root, err := LoadReader(strings.NewReader(text), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	frequencies := map[string]int{
+		"the":   1000,
+		"thee":  5,
+		"word":  10,
+		"empty": 0,
+	}
+
+	for word, frequency := range frequencies {
+		if !isWordLoaded(root, word, 0) {
+			t.Errorf("Word \"%s\" was not loaded.\n", word)
+		}
+
+		if found := root.Find(word).Frequency(); found != frequency {
+			t.Errorf("Expected \"%s\" to have frequency %d, found %d.\n", word, frequency, found)
+		}
+	}
+
+	// Invalid frequencies are ignored
+	for text, frequency := range map[string]int{"word\tmany\n": 0, "word\t-1\n": 0, "word\t1\t2\n": 1} {
+		root, err := LoadReader(strings.NewReader(text), Options{})
+		if err != nil || !root.Contains("word") || root.Find("word").Frequency() != frequency {
+			t.Errorf("Expected \"word\" with frequency %d loading %q, found %v.", frequency, text, err)
+		}
+	}
+}
+
+// Test loading words with compound flags.
+func TestLoadingFlags(t *testing.T) {
+	text := "haus\t120\tBM\ntuer\tE\nzeit\tC\nhaus\tE\nnasa\tN\nword\n"
+	root, err := LoadReader(strings.NewReader(text), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	flags := map[string]Flags{
+		"haus": CompoundBegin | CompoundMiddle | CompoundEnd,
+		"tuer": CompoundEnd,
+		"zeit": CompoundAny,
+		"nasa": NoCompound,
+		"word": 0,
+	}
+
+	for word, f := range flags {
+		if found := root.Find(word).Flags(); found != f {
+			t.Errorf("Expected \"%s\" to have flags %q, found %q.\n", word, f, found)
+		}
+	}
+
+	if found := root.Find("haus").Frequency(); found != 120 {
+		t.Errorf("Expected \"haus\" to have frequency 120, found %d.\n", found)
+	}
+
+	// Invalid flags and extra columns are ignored
+	for text, f := range map[string]Flags{"word\tBX\n": 0, "word\tB\tE\n": CompoundBegin, "word\t1\tB\tE\n": CompoundBegin} {
+		root, err := LoadReader(strings.NewReader(text), Options{})
+		if err != nil || !root.Contains("word") || root.Find("word").Flags() != f {
+			t.Errorf("Expected \"word\" with flags %q loading %q, found %v.", f, text, err)
+		}
+	}
+
+	// They are problems when validating
+	for _, text := range []string{"word\tBX\n", "word\tB\tE\n", "word\t1\tB\tE\n"} {
+		var validationErr *ValidationError
+		_, err := LoadReader(strings.NewReader(text), Options{Validate: true})
+		if !errors.As(err, &validationErr) || validationErr.Problems[0].Kind != InvalidEntry {
+			t.Errorf("Expected an invalid entry validating %q, found %v.", text, err)
+		}
+	}
+}
+
+// Test removing words, and pruning of nodes left without words.
+func TestRemovingWords(t *testing.T) {
+	root := LoadList([]string{"a", "an", "and", "ant", "bee"})
+	if root.Len() != 5 {
+		t.Errorf("Expected 5 words, found %d.", root.Len())
+	}
+
+	root = RemoveWord(root, "an")
+	root = RemoveWord(root, "bee")
+	root = RemoveWord(root, "missing")
+	root = RemoveWord(root, "b")
+
+	for word, expected := range map[string]bool{
+		"a":   true,
+		"an":  false,
+		"and": true,
+		"ant": true,
+		"bee": false,
+		"be":  false,
+	} {
+		if found := root.Contains(word); found != expected {
+			t.Errorf("Expected Contains(\"%s\") to be %t, found %t.", word, expected, found)
+		}
+	}
+
+	if root.Len() != 3 {
+		t.Errorf("Expected 3 words, found %d.", root.Len())
+	}
+
+	// Branches without words are pruned, others are kept
+	if root.children['b'-FirstPrintableASCII] != nil {
+		t.Errorf("Expected branch of \"bee\" to be pruned.")
+	}
+
+	if root.Find("an") == nil {
+		t.Errorf("Expected branch of \"an\" to be kept.")
+	}
+
+	root = RemoveWord(root, "and")
+	root = RemoveWord(root, "ant")
+	if root.Find("an") != nil {
+		t.Errorf("Expected branch of \"an\" to be pruned.")
+	}
+
+	root.Clear()
+	if root.Len() != 0 || root.Contains("a") {
+		t.Errorf("Expected trie to be empty, found %d words.", root.Len())
+	}
+}
+
+// Test walking a trie, and listing words by prefix.
+func TestWalkingTrie(t *testing.T) {
+	root := LoadList([]string{"car", "cat", "Cat", "cart", "dog", "ca", "do"})
+
+	words := root.Words()
+	expected := []string{"Cat", "ca", "car", "cart", "cat", "do", "dog"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("Expected words %v, found %v.", expected, words)
+	}
+
+	if found := root.WithPrefix("car", 0); !reflect.DeepEqual(found, []string{"car", "cart"}) {
+		t.Errorf("Expected words [car cart], found %v.", found)
+	}
+
+	if found := root.WithPrefix("ca", 2); !reflect.DeepEqual(found, []string{"ca", "car"}) {
+		t.Errorf("Expected words [ca car], found %v.", found)
+	}
+
+	if found := root.WithPrefix("x", 0); len(found) != 0 {
+		t.Errorf("Expected no words, found %v.", found)
+	}
+
+	for prefix, count := range map[string]int{"": 7, "ca": 4, "cart": 1, "e": 0} {
+		if found := root.CountPrefix(prefix); found != count {
+			t.Errorf("Expected %d words starting with \"%s\", found %d.", count, prefix, found)
+		}
+	}
+
+	for s, prefix := range map[string]string{"cartoon": "cart", "cab": "ca", "dot": "do", "c": "", "ox": ""} {
+		found, ok := root.LongestPrefix(s)
+		if found != prefix || ok != (prefix != "") {
+			t.Errorf("Expected longest prefix of \"%s\" to be \"%s\", found \"%s\".", s, prefix, found)
+		}
+	}
+
+	// Walking stops when fn returns false
+	visited := 0
+	if root.Walk(func(string, *Node) bool { visited++; return visited < 3 }) || visited != 3 {
+		t.Errorf("Expected walking to stop after 3 words, visited %d.", visited)
+	}
+
+	if root.Child('c').Child('a') != root.Find("ca") || root.Child('\n') != nil {
+		t.Errorf("Child returned an unexpected node.")
+	}
+}
+
+// Test writing a trie, and loading the written dictionary.
+func TestWritingTrie(t *testing.T) {
+	text := "word\nthe\t1000\nhaus\t12\tBM\ntuer\tE\nThe\n"
+	root, err := LoadReader(strings.NewReader(text), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	for _, test := range []struct {
+		opts     WriteOptions
+		expected string
+	}{
+		{WriteOptions{}, "The\nhaus\nthe\ntuer\nword\n"},
+		{WriteOptions{Frequencies: true}, "The\nhaus\t12\nthe\t1000\ntuer\nword\n"},
+		{WriteOptions{Flags: true}, "The\nhaus\tBM\nthe\ntuer\tE\nword\n"},
+		{WriteOptions{true, true}, "The\nhaus\t12\tBM\nthe\t1000\ntuer\tE\nword\n"},
+	} {
+		builder := new(strings.Builder)
+		n, err := root.WriteToOptions(builder, test.opts)
+		if err != nil {
+			t.Fatalf("Writing failed: %v.", err)
+		}
+
+		if builder.String() != test.expected {
+			t.Errorf("Expected %q, found %q.", test.expected, builder.String())
+		}
+
+		if n != int64(builder.Len()) {
+			t.Errorf("Expected %d bytes written, found %d.", builder.Len(), n)
+		}
+	}
+
+	// Written tries can be loaded again
+	builder := new(strings.Builder)
+	if _, err := root.WriteToOptions(builder, WriteOptions{true, true}); err != nil {
+		t.Fatalf("Writing failed: %v.", err)
+	}
+
+	loaded, err := LoadReader(strings.NewReader(builder.String()), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	if !reflect.DeepEqual(loaded, root) {
+		t.Errorf("Expected loaded trie to equal written trie.")
+	}
+}
+
+// Test merging, intersecting, and subtracting tries.
+func TestSetOperations(t *testing.T) {
+	a, err := LoadReader(strings.NewReader("apple\t3\napply\tB\nbanana\ncherry\t1\n"), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	b, err := LoadReader(strings.NewReader("apple\t2\napply\tE\ncherry\ndate\n"), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	merged := Merge(a, b)
+	if words := merged.Words(); !reflect.DeepEqual(words, []string{"apple", "apply", "banana", "cherry", "date"}) {
+		t.Errorf("Expected merged words [apple apply banana cherry date], found %v.", words)
+	}
+
+	if found := merged.Find("apple").Frequency(); found != 5 {
+		t.Errorf("Expected \"apple\" to have frequency 5, found %d.", found)
+	}
+
+	if found := merged.Find("apply").Flags(); found != CompoundBegin|CompoundEnd {
+		t.Errorf("Expected \"apply\" to have flags \"BE\", found %q.", found)
+	}
+
+	intersection := Intersect(a, b)
+	if words := intersection.Words(); !reflect.DeepEqual(words, []string{"apple", "apply", "cherry"}) {
+		t.Errorf("Expected intersection [apple apply cherry], found %v.", words)
+	}
+
+	if found := intersection.Find("apple").Frequency(); found != 3 {
+		t.Errorf("Expected \"apple\" to have frequency 3, found %d.", found)
+	}
+
+	difference := Difference(a, b)
+	if words := difference.Words(); !reflect.DeepEqual(words, []string{"banana"}) {
+		t.Errorf("Expected difference [banana], found %v.", words)
+	}
+
+	// Branches without words are pruned
+	if difference.Find("a") != nil {
+		t.Errorf("Expected branch of \"apple\" to be pruned.")
+	}
+
+	if Difference(a, a).Len() != 0 || Intersect(a, new(Node)).Len() != 0 || Merge(nil, a).Len() != 4 {
+		t.Errorf("Unexpected result of an operation with an empty or equal trie.")
+	}
+
+	// Inputs are not modified
+	if a.Len() != 4 || b.Len() != 4 || a.Find("apple").Frequency() != 3 {
+		t.Errorf("Expected inputs to be unchanged.")
+	}
+}
+
+// Test skipping words containing non-printable or non-ASCII bytes.
+func TestLoadingNonPrintable(t *testing.T) {
+	root := LoadList([]string{"caf\xc3\xa9", "tab\tbed", "del\x7f", "word"})
+	if words := root.Words(); !reflect.DeepEqual(words, []string{"word"}) {
+		t.Errorf("Expected words [word], found %v.", words)
+	}
+
+	root, err := LoadReader(strings.NewReader("\n\nna\xefve\nword\n"), Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	if root.IsWord() || root.Len() != 1 {
+		t.Errorf("Expected only \"word\" to be loaded, found %v.", root.Words())
+	}
+}
+
+// Test reporting invalid lines when validating a dictionary.
+func TestValidation(t *testing.T) {
+	text := "apple\n\nbanana \n  \ncherry\napple\t3\nna\xefve\ndate\tmany\n" + strings.Repeat("x", 20) + "\nbanana\n"
+	root, err := LoadReader(strings.NewReader(text), Options{MaxLineLength: 10, Validate: true})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *ValidationError, found %v.", err)
+	}
+
+	expected := []Problem{
+		{1, BlankLine, ""},
+		{2, Whitespace, "banana "},
+		{3, BlankLine, "  "},
+		{5, Duplicate, "apple\t3"},
+		{6, NonPrintable, "na\xefve"},
+		{7, InvalidEntry, "date\tmany"},
+		{8, LongLine, ""},
+		{9, Duplicate, "banana"},
+	}
+
+	if !reflect.DeepEqual(validationErr.Problems, expected) {
+		t.Errorf("Expected problems %v, found %v.", expected, validationErr.Problems)
+	}
+
+	// Words are normalized
+	if words := root.Words(); !reflect.DeepEqual(words, []string{"apple", "banana", "cherry"}) {
+		t.Errorf("Expected words [apple banana cherry], found %v.", words)
+	}
+
+	if found := root.Find("apple").Frequency(); found != 3 {
+		t.Errorf("Expected \"apple\" to have frequency 3, found %d.", found)
+	}
+
+	if _, err := LoadReader(strings.NewReader("apple\nbanana\n"), Options{Validate: true}); err != nil {
+		t.Errorf("Expected no error, found %v.", err)
+	}
+}
+
 // Return true if word is correctly loaded, false otherwise.
 func isWordLoaded(root *Node, word string, whichChar int) bool {
 	if whichChar == len(word) {
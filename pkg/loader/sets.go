@@ -0,0 +1,114 @@
+package loader
+
+// Merge returns a new trie containing words of both a and b. Frequencies
+// of words in both are added, and their flags are combined. Either trie
+// may be nil.
+func Merge(a, b *Node) *Node {
+	if root := recMerge(a, b); root != nil {
+		return root
+	}
+
+	return new(Node)
+}
+
+// recMerge merges two tries recursively. Returns nil if both are nil.
+func recMerge(a, b *Node) *Node {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	root := new(Node)
+	for _, n := range []*Node{a, b} {
+		if n != nil && n.isWord {
+			root.isWord = true
+			root.frequency += n.frequency
+			root.flags |= n.flags
+		}
+	}
+
+	for i := range root.children {
+		var ac, bc *Node
+		if a != nil {
+			ac = a.children[i]
+		}
+
+		if b != nil {
+			bc = b.children[i]
+		}
+
+		root.children[i] = recMerge(ac, bc)
+	}
+
+	return root
+}
+
+// Intersect returns a new trie containing words that exist in both a
+// and b, with frequencies and flags of a.
+func Intersect(a, b *Node) *Node {
+	if root := recIntersect(a, b); root != nil {
+		return root
+	}
+
+	return new(Node)
+}
+
+// recIntersect intersects two tries recursively. Returns nil if the
+// intersection is empty.
+func recIntersect(a, b *Node) *Node {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	root := new(Node)
+	if a.isWord && b.isWord {
+		root.isWord, root.frequency, root.flags = true, a.frequency, a.flags
+	}
+
+	for i := range root.children {
+		root.children[i] = recIntersect(a.children[i], b.children[i])
+	}
+
+	if root.empty() {
+		return nil
+	}
+
+	return root
+}
+
+// Difference returns a new trie containing words of a that don't exist
+// in b, with frequencies and flags of a.
+func Difference(a, b *Node) *Node {
+	if root := recDifference(a, b); root != nil {
+		return root
+	}
+
+	return new(Node)
+}
+
+// recDifference computes the difference of two tries recursively.
+// Returns nil if the difference is empty.
+func recDifference(a, b *Node) *Node {
+	if a == nil {
+		return nil
+	}
+
+	root := new(Node)
+	if a.isWord && (b == nil || !b.isWord) {
+		root.isWord, root.frequency, root.flags = true, a.frequency, a.flags
+	}
+
+	for i := range root.children {
+		var bc *Node
+		if b != nil {
+			bc = b.children[i]
+		}
+
+		root.children[i] = recDifference(a.children[i], bc)
+	}
+
+	if root.empty() {
+		return nil
+	}
+
+	return root
+}
@@ -0,0 +1,110 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProblemKind is the kind of a problem found in a dictionary file.
+type ProblemKind int
+
+// Kinds of problems found in dictionary files.
+const (
+	BlankLine    ProblemKind = iota // Line is empty, or only whitespace
+	Whitespace                      // Word has leading or trailing whitespace
+	Duplicate                       // Word was already loaded
+	NonPrintable                    // Word contains bytes other than printable ASCII
+	InvalidEntry                    // Line has invalid columns, such as a bad frequency
+	LongLine                        // Line is longer than Options.MaxLineLength
+)
+
+// String returns the kind's name.
+func (k ProblemKind) String() string {
+	switch k {
+	case BlankLine:
+		return "blank-line"
+	case Whitespace:
+		return "whitespace"
+	case Duplicate:
+		return "duplicate"
+	case NonPrintable:
+		return "non-printable"
+	case InvalidEntry:
+		return "invalid-entry"
+	case LongLine:
+		return "long-line"
+	default:
+		return fmt.Sprintf("problem(%d)", int(k))
+	}
+}
+
+// Problem is a problem found in a line of a dictionary file.
+type Problem struct {
+	Row  int         // Row of the line, starting at 0
+	Kind ProblemKind // Kind of the problem
+	Line string      // Text of the line, empty for long lines
+}
+
+// String returns a description of the problem.
+func (p Problem) String() string {
+	return fmt.Sprintf("row %d: %s %q", p.Row, p.Kind, p.Line)
+}
+
+// ValidationError is returned when problems are found in a dictionary
+// read with Options.Validate set. Problems are not fatal, so it's
+// returned alongside a valid result.
+type ValidationError struct {
+	Problems []Problem // Problems found, in order of rows
+}
+
+// Error returns a description of the error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s) found in dictionary, first at %s", len(e.Problems), e.Problems[0])
+}
+
+// validate checks a line of a dictionary file, and returns the word it
+// contains normalized, or false if it should be skipped. Found problems
+// are appended to problems.
+func validate(root *Node, row int, line string, problems *[]Problem) (string, int, Flags, bool) {
+	report := func(kind ProblemKind) {
+		*problems = append(*problems, Problem{row, kind, line})
+	}
+
+	if strings.TrimSpace(line) == "" {
+		report(BlankLine)
+		return "", 0, 0, false
+	}
+
+	word, frequency, flags, err := parseLine(line, true)
+	if err != nil {
+		report(InvalidEntry)
+		return "", 0, 0, false
+	}
+
+	if trimmed := strings.TrimSpace(word); trimmed != word {
+		report(Whitespace)
+		word = trimmed
+	}
+
+	switch {
+	case !printable(word):
+		report(NonPrintable)
+		return "", 0, 0, false
+	case root.Contains(word):
+		report(Duplicate)
+	}
+
+	return word, frequency, flags, true
+}
+
+// printable returns true if word contains only printable ASCII
+// characters.
+func printable(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if word[i] < FirstPrintableASCII || word[i] >= FirstPrintableASCII+PrintableASCII {
+			return false
+		}
+	}
+
+	return true
+}
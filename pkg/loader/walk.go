@@ -0,0 +1,106 @@
+package loader
+
+// Child returns the child of node for the given character, or nil if
+// there's none, or the character is not printable ASCII. Unlike
+// Children, it doesn't copy node's children.
+func (n *Node) Child(c byte) *Node {
+	if c < FirstPrintableASCII || c >= FirstPrintableASCII+PrintableASCII {
+		return nil
+	}
+
+	return n.children[c-FirstPrintableASCII]
+}
+
+// Find returns the node at which word ends, or nil if there's none. The
+// node is part of the trie, and is a word only if IsWord returns true.
+func (n *Node) Find(word string) *Node {
+	node := n
+	for i := 0; i < len(word) && node != nil; i++ {
+		node = node.Child(word[i])
+	}
+
+	return node
+}
+
+// WalkFunc is called by Walk for each word of a trie with the word and
+// the node at which it ends. Walking stops if it returns false.
+type WalkFunc func(word string, node *Node) bool
+
+// Walk calls fn for each word of trie in lexical (byte) order. Returns
+// false if walking was stopped by fn, true otherwise.
+func (n *Node) Walk(fn WalkFunc) bool {
+	return n.walk(make([]byte, 0, 32), fn)
+}
+
+// WalkPrefix calls fn for each word of trie starting with prefix in
+// lexical order. Returns false if walking was stopped by fn, true
+// otherwise.
+func (n *Node) WalkPrefix(prefix string, fn WalkFunc) bool {
+	node := n.Find(prefix)
+	if node == nil {
+		return true
+	}
+
+	return node.walk([]byte(prefix), fn)
+}
+
+// walk, recursively, calls fn for each word ending at or under node.
+// prefix is the path to node.
+func (n *Node) walk(prefix []byte, fn WalkFunc) bool {
+	if n.isWord && !fn(string(prefix), n) {
+		return false
+	}
+
+	for i, child := range &n.children {
+		if child != nil && !child.walk(append(prefix, byte(i+FirstPrintableASCII)), fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Words returns all words of trie in lexical order.
+func (n *Node) Words() []string {
+	return n.WithPrefix("", 0)
+}
+
+// WithPrefix returns words of trie starting with prefix in lexical
+// order, at most max words if max is positive.
+func (n *Node) WithPrefix(prefix string, max int) []string {
+	var words []string
+	n.WalkPrefix(prefix, func(word string, _ *Node) bool {
+		words = append(words, word)
+		return max <= 0 || len(words) < max
+	})
+
+	return words
+}
+
+// CountPrefix returns the number of words of trie starting with prefix.
+func (n *Node) CountPrefix(prefix string) int {
+	node := n.Find(prefix)
+	if node == nil {
+		return 0
+	}
+
+	return node.Len()
+}
+
+// LongestPrefix returns the longest prefix of s that is a word of trie,
+// and false if there's none.
+func (n *Node) LongestPrefix(s string) (string, bool) {
+	longest, found := 0, n.isWord
+	node := n
+	for i := 0; i < len(s); i++ {
+		if node = node.Child(s[i]); node == nil {
+			break
+		}
+
+		if node.isWord {
+			longest, found = i+1, true
+		}
+	}
+
+	return s[:longest], found
+}
@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// WriteOptions configures writing of a trie as a dictionary file.
+type WriteOptions struct {
+	Frequencies bool // Write frequencies of words, if known
+	Flags       bool // Write flags of words, if any
+}
+
+// WriteTo writes words of trie to w in lexical order, one word per
+// line. Returns the number of bytes written, and an error if writing
+// fails.
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	return n.WriteToOptions(w, WriteOptions{})
+}
+
+// WriteToOptions writes words of trie to w in lexical order, one word
+// per line, using the format read by LoadReader. Returns the number of
+// bytes written, and an error if writing fails.
+func (n *Node) WriteToOptions(w io.Writer, opts WriteOptions) (int64, error) {
+	cw := &countingWriter{w: w}
+	buf := bufio.NewWriter(cw)
+
+	var err error
+	n.Walk(func(word string, node *Node) bool {
+		line := word
+		if opts.Frequencies && node.frequency != 0 {
+			line += "\t" + strconv.Itoa(node.frequency)
+		}
+
+		if opts.Flags && node.flags != 0 {
+			line += "\t" + node.flags.String()
+		}
+
+		_, err = buf.WriteString(line + "\n")
+		return err == nil
+	})
+
+	if err == nil {
+		err = buf.Flush()
+	}
+
+	return cw.n, err
+}
+
+// countingWriter is a writer that counts bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes p to w.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
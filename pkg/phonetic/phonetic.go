@@ -0,0 +1,250 @@
+// Package phonetic implements phonetic encoding of words, and an index
+// of dictionary words by their encoding, used to find words that sound
+// alike, such as "fonetik" and "phonetic".
+//
+// Words are encoded using the Metaphone algorithm, which maps a word to
+// a key representing its pronunciation in English. Words that sound
+// alike share a key.
+//
+//	phonetic.Encode("fonetik") // FNTK
+//	phonetic.Encode("phonetic") // FNTK
+package phonetic
+
+import (
+	"strings"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// Match is a word of an Index that sounds like a looked up word.
+type Match struct {
+	Word      string // Dictionary word
+	Frequency int    // Frequency of the word in the dictionary
+}
+
+// Index maps phonetic keys to dictionary words with that key.
+type Index struct {
+	keys map[string][]Match
+}
+
+// NewIndex creates an Index of all words of a given Trie. It's meant
+// to be built once, after loading a dictionary.
+func NewIndex(root *loader.Node) *Index {
+	idx := &Index{make(map[string][]Match)}
+	root.Walk(func(word string, node *loader.Node) bool {
+		idx.Add(word, node.Frequency())
+		return true
+	})
+
+	return idx
+}
+
+// Add adds a word with a given frequency to the index. Words without
+// letters are ignored.
+func (idx *Index) Add(word string, frequency int) {
+	key := Encode(word)
+	if key != "" {
+		idx.keys[key] = append(idx.keys[key], Match{word, frequency})
+	}
+}
+
+// Lookup returns words of the index that sound like word.
+func (idx *Index) Lookup(word string) []Match {
+	key := Encode(word)
+	if key == "" {
+		return nil
+	}
+
+	return idx.keys[key]
+}
+
+// Encode returns the Metaphone key of a word. Only ASCII letters are
+// encoded, other characters are ignored.
+func Encode(word string) string {
+	w := letters(word)
+	if len(w) == 0 {
+		return ""
+	}
+
+	// Initial exceptions
+	switch {
+	case hasPrefix(w, "ae", "gn", "kn", "pn", "wr"):
+		w = w[1:]
+	case w[0] == 'x':
+		w[0] = 's'
+	case hasPrefix(w, "wh"):
+		w = append([]byte{'w'}, w[2:]...)
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+
+		return w[i]
+	}
+
+	key := make([]byte, 0, len(w))
+	for i := 0; i < len(w); i++ {
+		switch b := w[i]; b {
+		case 'a', 'e', 'i', 'o', 'u':
+			if i == 0 {
+				key = append(key, upper(b))
+			}
+
+		case 'b':
+			// Silent in a final "mb", as in "dumb"
+			if i != len(w)-1 || at(i-1) != 'm' {
+				key = append(key, 'B')
+			}
+
+		case 'c':
+			switch {
+			case at(i+1) == 'i' && at(i+2) == 'a':
+				key = append(key, 'X')
+			case at(i+1) == 'h':
+				if at(i-1) == 's' {
+					key = append(key, 'K')
+				} else {
+					key = append(key, 'X')
+				}
+			case isFront(at(i + 1)):
+				if at(i-1) != 's' {
+					key = append(key, 'S')
+				}
+			default:
+				key = append(key, 'K')
+			}
+
+		case 'd':
+			if at(i+1) == 'g' && isFront(at(i+2)) {
+				key = append(key, 'J')
+				i++
+			} else {
+				key = append(key, 'T')
+			}
+
+		case 'g':
+			switch {
+			case at(i+1) == 'h' && i+2 < len(w) && !isVowel(at(i+2)):
+				// Silent, as in "night"
+			case at(i+1) == 'n' && (i+2 == len(w) || (at(i+2) == 'e' && at(i+3) == 'd' && i+4 == len(w))):
+				// Silent, as in "sign" and "signed"
+			case isFront(at(i+1)) && at(i-1) != 'g':
+				key = append(key, 'J')
+			default:
+				key = append(key, 'K')
+			}
+
+		case 'h':
+			silent := (isVowel(at(i-1)) && !isVowel(at(i+1))) || strings.IndexByte("cgpst", at(i-1)) >= 0
+			if !silent || i == 0 {
+				key = append(key, 'H')
+			}
+
+		case 'k':
+			if at(i-1) != 'c' {
+				key = append(key, 'K')
+			}
+
+		case 'p':
+			if at(i+1) == 'h' {
+				key = append(key, 'F')
+			} else {
+				key = append(key, 'P')
+			}
+
+		case 'q':
+			key = append(key, 'K')
+
+		case 's':
+			if at(i+1) == 'h' || (at(i+1) == 'i' && (at(i+2) == 'o' || at(i+2) == 'a')) {
+				key = append(key, 'X')
+			} else {
+				key = append(key, 'S')
+			}
+
+		case 't':
+			switch {
+			case at(i+1) == 'i' && (at(i+2) == 'o' || at(i+2) == 'a'):
+				key = append(key, 'X')
+			case at(i+1) == 'h':
+				key = append(key, '0')
+			case at(i+1) == 'c' && at(i+2) == 'h':
+				// Silent, as in "watch"
+			default:
+				key = append(key, 'T')
+			}
+
+		case 'v':
+			key = append(key, 'F')
+
+		case 'w', 'y':
+			if isVowel(at(i + 1)) {
+				key = append(key, upper(b))
+			}
+
+		case 'x':
+			key = append(key, 'K', 'S')
+
+		case 'z':
+			key = append(key, 'S')
+
+		default:
+			key = append(key, upper(b))
+		}
+	}
+
+	return string(key)
+}
+
+// letters returns the lowercase ASCII letters of a word, without
+// adjacent duplicates, except for "cc".
+func letters(word string) []byte {
+	w := make([]byte, 0, len(word))
+	for i := 0; i < len(word); i++ {
+		b := word[i]
+		if b >= 'A' && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+
+		if b < 'a' || b > 'z' {
+			continue
+		}
+
+		if len(w) > 0 && w[len(w)-1] == b && b != 'c' {
+			continue
+		}
+
+		w = append(w, b)
+	}
+
+	return w
+}
+
+// hasPrefix returns true if w starts with one of given prefixes.
+func hasPrefix(w []byte, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(string(w), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isVowel returns true if b is a lowercase vowel.
+func isVowel(b byte) bool {
+	return b != 0 && strings.IndexByte("aeiou", b) >= 0
+}
+
+// isFront returns true if b is a front vowel, which softens "c" and
+// "g" before it.
+func isFront(b byte) bool {
+	return b == 'e' || b == 'i' || b == 'y'
+}
+
+// upper returns the uppercase variant of a lowercase ASCII letter.
+func upper(b byte) byte {
+	return b - 'a' + 'A'
+}
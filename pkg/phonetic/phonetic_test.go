@@ -0,0 +1,57 @@
+package phonetic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sudo-sturbia/gocheck/v3/pkg/loader"
+)
+
+// Test encoding words.
+func TestEncode(t *testing.T) {
+	keys := map[string]string{
+		"phonetic":  "FNTK",
+		"fonetik":   "FNTK",
+		"Knight":    "NT",
+		"night":     "NT",
+		"science":   "SNS",
+		"thumb":     "0M",
+		"which":     "WX",
+		"xylophone": "SLFN",
+		"judge":     "JJ",
+		"nation":    "NXN",
+		"apple":     "APL",
+		"":          "",
+		"123":       "",
+	}
+
+	for word, key := range keys {
+		if found := Encode(word); found != key {
+			t.Errorf("Expected %q to be encoded as %q, found %q.", word, key, found)
+		}
+	}
+}
+
+// Test looking up sound-alike words.
+func TestIndex(t *testing.T) {
+	root, err := loader.LoadReader(strings.NewReader("phonetic\t20\nfanatic\t5\nknight\nnight\t7\nword\n"), loader.Options{})
+	if err != nil {
+		t.Fatalf("Loading failed: %v.", err)
+	}
+
+	idx := NewIndex(root)
+
+	matches := idx.Lookup("fonetik")
+	if len(matches) != 2 || matches[0] != (Match{"fanatic", 5}) || matches[1] != (Match{"phonetic", 20}) {
+		t.Errorf("Expected to find \"fanatic\" and \"phonetic\", found %v.", matches)
+	}
+
+	matches = idx.Lookup("nite")
+	if len(matches) != 2 {
+		t.Errorf("Expected to find \"knight\" and \"night\", found %v.", matches)
+	}
+
+	if matches := idx.Lookup("zzz"); len(matches) != 0 {
+		t.Errorf("Expected no matches, found %v.", matches)
+	}
+}